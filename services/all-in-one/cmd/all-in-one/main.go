@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/all-in-one/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/all-in-one/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/memory"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	cfg, err := config.LoadConfig("./configs/config.yaml")
+	if err != nil {
+		slog.Error("failed to load initial configuration, shutting down", "error", err)
+		os.Exit(1)
+	}
+
+	var log logger.Logger = logger.New(cfg.Env)
+
+	log.Info("starting all-in-one", slog.String("env", cfg.Env))
+	log.Debug("debug messages are enabled")
+
+	serviceConfigs, err := loadServiceConfigs(cfg.Services)
+	if err != nil {
+		log.Error("failed to load service configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Metric names are prefixed per service, so one registry holds them all.
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector())
+
+	bus := memory.NewBus(cfg.Bus.BufferSize)
+	defer bus.Close()
+
+	pipelines, err := newPipelines(serviceConfigs, bus, log, reg)
+	if err != nil {
+		log.Error("failed to create pipelines", "error", err)
+		os.Exit(1)
+	}
+
+	go func() {
+		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+		log.Info("metrics server listening", "port", cfg.Server.Port)
+		if err := http.ListenAndServe(":"+cfg.Server.Port, nil); err != nil {
+			log.Error("failed to start metrics server", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		cancel()
+	}()
+
+	var wg sync.WaitGroup
+	for _, p := range pipelines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			log.Info("pipeline started", "pipeline", p.name)
+			if err := p.runner.Run(ctx); err != nil {
+				log.Error("pipeline failed", "pipeline", p.name, "error", err)
+			}
+
+			// One pipeline stopping takes the process down, the way a failed
+			// container would be restarted on its own.
+			cancel()
+		}()
+	}
+
+	wg.Wait()
+
+	log.Info("all-in-one gracefully stopped")
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/all-in-one/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/all-in-one/pkg/logger"
+	apiApp "github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/app"
+	apiConsumer "github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/consumer"
+	apiMemory "github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/memory"
+	cacheApp "github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/app"
+	cacheMemory "github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/memory"
+	collectorApp "github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/app"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/memory"
+	notificationApp "github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/app"
+	notificationMemory "github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/memory"
+	persisterApp "github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/app"
+	persisterMemory "github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/memory"
+	analyticsApp "github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/pkg/app"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type runner interface {
+	Run(ctx context.Context) error
+}
+
+type pipeline struct {
+	name   string
+	runner runner
+}
+
+// serviceConfigs holds the configuration of every enabled service. They are
+// all loaded before any pipeline is built because the services read them
+// through viper's global instance.
+type serviceConfigs struct {
+	collector    *collectorApp.Config
+	persister    *persisterApp.Config
+	cache        *cacheApp.Config
+	api          *apiApp.Config
+	analytics    *analyticsApp.Config
+	notification *notificationApp.Config
+}
+
+func loadServiceConfigs(cfg config.ServicesConfig) (serviceConfigs, error) {
+	var (
+		configs serviceConfigs
+		err     error
+	)
+
+	if cfg.Collector.Enabled {
+		if configs.collector, err = collectorApp.LoadConfig(cfg.Collector.ConfigPath); err != nil {
+			return configs, fmt.Errorf("failed to load collector config: %w", err)
+		}
+	}
+	if cfg.Persister.Enabled {
+		if configs.persister, err = persisterApp.LoadConfig(cfg.Persister.ConfigPath); err != nil {
+			return configs, fmt.Errorf("failed to load persister config: %w", err)
+		}
+	}
+	if cfg.Cache.Enabled {
+		if configs.cache, err = cacheApp.LoadConfig(cfg.Cache.ConfigPath); err != nil {
+			return configs, fmt.Errorf("failed to load cache config: %w", err)
+		}
+	}
+	if cfg.API.Enabled {
+		if configs.api, err = apiApp.LoadConfig(cfg.API.ConfigPath); err != nil {
+			return configs, fmt.Errorf("failed to load api config: %w", err)
+		}
+	}
+	if cfg.Analytics.Enabled {
+		if configs.analytics, err = analyticsApp.LoadConfig(cfg.Analytics.ConfigPath); err != nil {
+			return configs, fmt.Errorf("failed to load analytics config: %w", err)
+		}
+	}
+	if cfg.Notification.Enabled {
+		if configs.notification, err = notificationApp.LoadConfig(cfg.Notification.ConfigPath); err != nil {
+			return configs, fmt.Errorf("failed to load notification config: %w", err)
+		}
+	}
+
+	// Services running in this process reach each other over loopback
+	// instead of the container hostnames in their configs.
+	if configs.collector != nil && configs.cache != nil {
+		configs.collector.Urls.CacheService = "localhost:" + configs.cache.GRPC.Port
+	}
+	if configs.analytics != nil && configs.api != nil {
+		configs.analytics.Urls.ApiService = "localhost:" + configs.api.GRPC.Port
+	}
+
+	return configs, nil
+}
+
+// newPipelines builds every enabled pipeline. Consumers subscribe to the bus
+// before the collector is created, so no metric is published ahead of them.
+// There is no Kafka to dead-letter to, so messages the consumers give up on
+// are logged and dropped.
+func newPipelines(configs serviceConfigs, bus *memory.Bus, log logger.Logger, reg *prometheus.Registry) ([]pipeline, error) {
+	var pipelines []pipeline
+
+	if configs.persister != nil {
+		cons := persisterMemory.NewMemoryConsumer(bus.Subscribe("persister"))
+		persister, err := persisterApp.New(configs.persister, cons, nil, serviceLogger(log, "persister"), reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create persister: %w", err)
+		}
+		pipelines = append(pipelines, pipeline{name: "persister", runner: persister})
+	}
+
+	if configs.cache != nil {
+		cons := cacheMemory.NewMemoryConsumer(bus.Subscribe("cache"))
+		cache, err := cacheApp.New(configs.cache, cons, nil, serviceLogger(log, "cache"), reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cache: %w", err)
+		}
+		pipelines = append(pipelines, pipeline{name: "cache", runner: cache})
+	}
+
+	if configs.notification != nil {
+		cons := notificationMemory.NewMemoryConsumer(bus.Subscribe("notification"))
+		notification, err := notificationApp.New(configs.notification, cons, nil, serviceLogger(log, "notification"), reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create notification: %w", err)
+		}
+		pipelines = append(pipelines, pipeline{name: "notification", runner: notification})
+	}
+
+	if configs.api != nil {
+		var cons apiConsumer.MessageConsumer
+		if configs.api.Subscriptions.Enabled {
+			cons = apiMemory.NewMemoryConsumer(bus.Subscribe("api"))
+		}
+
+		api, err := apiApp.New(configs.api, cons, serviceLogger(log, "api"), reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create api: %w", err)
+		}
+		pipelines = append(pipelines, pipeline{name: "api", runner: api})
+	}
+
+	if configs.analytics != nil {
+		analytics, err := analyticsApp.New(configs.analytics, serviceLogger(log, "analytics"), reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create analytics: %w", err)
+		}
+		pipelines = append(pipelines, pipeline{name: "analytics", runner: analytics})
+	}
+
+	if configs.collector != nil {
+		// The in-memory bus never rejects a batch, so spooling would only
+		// add disk writes.
+		configs.collector.Broker.Spool.Enabled = false
+
+		collector, err := collectorApp.New(configs.collector, memory.NewMemoryBroker(bus), serviceLogger(log, "collector"), reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create collector: %w", err)
+		}
+		pipelines = append(pipelines, pipeline{name: "collector", runner: collector})
+	}
+
+	return pipelines, nil
+}
+
+func serviceLogger(log logger.Logger, service string) logger.Logger {
+	if l, ok := log.(*slog.Logger); ok {
+		return l.With("service", service)
+	}
+
+	return log
+}
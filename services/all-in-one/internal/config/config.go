@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Env      string         `mapstructure:"env"`
+	Server   ServerConfig   `mapstructure:"server"`
+	Bus      BusConfig      `mapstructure:"bus"`
+	Services ServicesConfig `mapstructure:"services"`
+}
+
+type ServerConfig struct {
+	Port string `mapstructure:"port"`
+}
+
+type BusConfig struct {
+	BufferSize int `mapstructure:"buffer_size"`
+}
+
+type ServicesConfig struct {
+	Collector    ServiceConfig `mapstructure:"collector"`
+	Persister    ServiceConfig `mapstructure:"persister"`
+	Cache        ServiceConfig `mapstructure:"cache"`
+	API          ServiceConfig `mapstructure:"api"`
+	Analytics    ServiceConfig `mapstructure:"analytics"`
+	Notification ServiceConfig `mapstructure:"notification"`
+}
+
+// ServiceConfig points at the service's own configuration file, which is
+// loaded unchanged apart from its broker settings.
+type ServiceConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	ConfigPath string `mapstructure:"config"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	viper.SetConfigFile(path)
+
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	viper.AutomaticEnv()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return &cfg, nil
+}
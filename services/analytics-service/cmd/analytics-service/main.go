@@ -8,20 +8,13 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
-	"time"
 
-	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/aggregator"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/config"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/database"
-	promMetrics "github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/metrics"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/processor"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/pkg/grpc"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/pkg/app"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/pkg/logger"
 	"github.com/fsnotify/fsnotify"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func main() {
@@ -56,45 +49,21 @@ func main() {
 	})
 
 	cfgMutex.RLock()
-	mongoConfig := cfg.Mongo
-	metrics := cfg.Metrics
-	urls := cfg.Urls
-	serverConfig := cfg.Server
+	appConfig := *cfg
 	cfgMutex.RUnlock()
 
 	reg := prometheus.NewRegistry()
-	m := promMetrics.NewMetrics(reg)
 
-	mongoClient, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoConfig.URI()))
+	analytics, err := app.New(&appConfig, log, reg)
 	if err != nil {
-		log.Error("failed to connect to MongoDB", "uri", mongoConfig.URI(), "error", err)
+		log.Error("failed to start analytics", "error", err)
 		os.Exit(1)
 	}
-	defer mongoClient.Disconnect(context.Background())
-
-	if err := mongoClient.Ping(context.Background(), nil); err != nil {
-		log.Error("failed to ping MpngoDB", "error", err)
-		os.Exit(1)
-	}
-	log.Info("MongoDB connected successfully")
-
-	writer := database.NewMongoAnalyticsWriter(mongoClient, mongoConfig.DBName, mongoConfig.Collection)
-
-	apiClient, err := grpc.NewMetricsClient(urls.ApiService)
-	if err != nil {
-		log.Error("failed to connect to API service", "error", err)
-		os.Exit(1)
-	}
-	defer apiClient.Close()
-
-	aggregator := aggregator.NewAggregator(apiClient, writer, m)
-
-	processor := processor.NewProcessor(aggregator, log, time.Minute, m)
 
 	go func() {
 		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-		log.Info("metrics server listening", "port", serverConfig.Port)
-		if err := http.ListenAndServe(":"+serverConfig.Port, nil); err != nil {
+		log.Info("metrics server listening", "port", appConfig.Server.Port)
+		if err := http.ListenAndServe(":"+appConfig.Server.Port, nil); err != nil {
 			log.Error("failed to start metrics server", "error", err)
 			os.Exit(1)
 		}
@@ -103,14 +72,17 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	log.Info("starting analytics-service")
-	processor.Start(ctx, metrics)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+		cancel()
+	}()
 
-	cancel()
+	if err := analytics.Run(ctx); err != nil {
+		log.Error("failed to release resources", "error", err)
+	}
 
 	log.Info("service gracefully stopped")
 }
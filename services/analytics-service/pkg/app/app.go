@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/aggregator"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/database"
+	promMetrics "github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/internal/processor"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/pkg/grpc"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/servises/analytics-service/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Config is aliased so commands outside this module can load and adjust the
+// analytics configuration.
+type Config = config.Config
+
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// App periodically aggregates metrics read from the api service into
+// MongoDB. The standalone service and the all-in-one command both run it.
+type App struct {
+	metrics     []config.MetricInfo
+	mongoClient *mongo.Client
+	apiClient   *grpc.MetricsClient
+	processor   *processor.Processor
+	log         logger.Logger
+}
+
+func New(cfg *Config, log logger.Logger, reg *prometheus.Registry) (*App, error) {
+	m := promMetrics.NewMetrics(reg)
+
+	mongoClient, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.Mongo.URI()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	if err := mongoClient.Ping(context.Background(), nil); err != nil {
+		mongoClient.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+	log.Info("MongoDB connected successfully")
+
+	writer := database.NewMongoAnalyticsWriter(mongoClient, cfg.Mongo.DBName, cfg.Mongo.Collection)
+
+	apiClient, err := grpc.NewMetricsClient(cfg.Urls.ApiService)
+	if err != nil {
+		mongoClient.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to connect to API service: %w", err)
+	}
+
+	aggregator := aggregator.NewAggregator(apiClient, writer, m)
+
+	return &App{
+		metrics:     cfg.Metrics,
+		mongoClient: mongoClient,
+		apiClient:   apiClient,
+		processor:   processor.NewProcessor(aggregator, log, time.Minute, m),
+		log:         log,
+	}, nil
+}
+
+// Run aggregates until ctx is cancelled, then closes the api client and
+// disconnects from MongoDB.
+func (a *App) Run(ctx context.Context) error {
+	a.log.Info("starting analytics-service")
+	a.processor.Start(ctx, a.metrics)
+
+	return errors.Join(a.apiClient.Close(), a.mongoClient.Disconnect(context.Background()))
+}
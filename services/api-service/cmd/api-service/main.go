@@ -1,8 +1,8 @@
 package main
 
 import (
+	"context"
 	"log/slog"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,15 +10,11 @@ import (
 	"syscall"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/config"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/database"
-	grpcInternal "github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/grpc"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/app"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/logger"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/proto"
 	"github.com/fsnotify/fsnotify"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"google.golang.org/grpc"
 )
 
 func main() {
@@ -53,56 +49,41 @@ func main() {
 	})
 
 	cfgMutex.RLock()
-	dbConfig := cfg.Postgres
-	grpcConfig := cfg.GRPC
-	serverConfig := cfg.Server
+	appConfig := *cfg
 	cfgMutex.RUnlock()
 
 	reg := prometheus.NewRegistry()
-	m := metrics.NewMetrics(reg)
 
-	db, err := database.New(dbConfig)
+	api, err := app.New(&appConfig, app.NewConsumer(appConfig.Subscriptions), log, reg)
 	if err != nil {
-		log.Error("failed to connect to database", "error", err)
-		os.Exit(1)
-	}
-	defer db.Close()
-	log.Info("database connected successfully")
-
-	reader := database.NewPostgresMetricsReeader(db)
-
-	grpcServer := grpc.NewServer()
-	apiServer := grpcInternal.NewServer(reader, m)
-	proto.RegisterMetricsServiceServer(grpcServer, apiServer)
-
-	lis, err := net.Listen("tcp", ":"+grpcConfig.Port)
-	if err != nil {
-		log.Error("failed to listen", "error", err)
+		log.Error("failed to start api", "error", err)
 		os.Exit(1)
 	}
 
-	go func() {
-		log.Info("gRPC server listening", "port", grpcConfig.Port)
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Error("failed to serve gRPC", "error", err)
-			os.Exit(1)
-		}
-	}()
-
 	go func() {
 		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-		log.Info("metrics server listening", "port", serverConfig.Port)
-		if err := http.ListenAndServe(":"+serverConfig.Port, nil); err != nil {
+		log.Info("metrics server listening", "port", appConfig.Server.Port)
+		if err := http.ListenAndServe(":"+appConfig.Server.Port, nil); err != nil {
 			log.Error("failed to start metrics server", "error", err)
 			os.Exit(1)
 		}
 	}()
 
-	log.Info("starting persister-service")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		cancel()
+	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	if err := api.Run(ctx); err != nil {
+		log.Error("api-service failed", "error", err)
+		os.Exit(1)
+	}
 
 	log.Info("service gracefully stopped")
 }
@@ -14,6 +14,8 @@ type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Postgres PostgresConfig `mapstructure:"postgres"`
 	GRPC     GRPCConfig     `mapstructure:"grpc"`
+
+	Subscriptions SubscriptionsConfig `mapstructure:"subscriptions"`
 }
 
 type ServerConfig struct {
@@ -22,14 +24,18 @@ type ServerConfig struct {
 	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
 }
 
+// PostgresConfig describes the database. Timescale must match the
+// persister's setting: with it, aggregated metrics are read from the
+// continuous aggregates instead of being computed from raw rows.
 type PostgresConfig struct {
-	Host     string     `mapstructure:"host"`
-	Port     string     `mapstructure:"port"`
-	User     string     `mapstructure:"user"`
-	Password string     `mapstructure:"password"`
-	DBName   string     `mapstructure:"dbname"`
-	SSLMode  string     `mapstructure:"sslmode"`
-	Pool     PoolConfig `mapstructure:"pool"`
+	Host      string     `mapstructure:"host"`
+	Port      string     `mapstructure:"port"`
+	User      string     `mapstructure:"user"`
+	Password  string     `mapstructure:"password"`
+	DBName    string     `mapstructure:"dbname"`
+	SSLMode   string     `mapstructure:"sslmode"`
+	Timescale bool       `mapstructure:"timescale"`
+	Pool      PoolConfig `mapstructure:"pool"`
 }
 
 type PoolConfig struct {
@@ -39,6 +45,25 @@ type PoolConfig struct {
 	ConnMaxIdleTime time.Duration `mapstrucutre:"conn_max_idle_time"`
 }
 
+// SubscriptionsConfig sets up the Subscribe stream. BufferSize is the number
+// of metrics queued for one subscriber before further metrics are dropped
+// for it.
+type SubscriptionsConfig struct {
+	Enabled        bool        `mapstructure:"enabled"`
+	BufferSize     int         `mapstructure:"buffer_size"`
+	MaxSubscribers int         `mapstructure:"max_subscribers"`
+	Kafka          KafkaConfig `mapstructure:"kafka"`
+}
+
+// KafkaConfig describes the topic subscriptions are fed from. The consumer
+// group is GroupPrefix followed by the host name, so every api-service
+// instance reads all of the topic's partitions.
+type KafkaConfig struct {
+	Brokers     []string `mapstructure:"brokers"`
+	Topic       string   `mapstructure:"topic"`
+	GroupPrefix string   `mapstructure:"group_prefix"`
+}
+
 type GRPCConfig struct {
 	Port string `mapstructure:"port"`
 }
@@ -1,8 +1,13 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/reader"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type MultipleSelectError struct {
@@ -24,3 +29,69 @@ func (e *MultipleSelectError) Error() string {
 
 	return sb.String()
 }
+
+type UnsupportedResolutionError struct {
+	Resolution models.Resolution
+}
+
+func (e UnsupportedResolutionError) Error() string {
+	return fmt.Sprintf("unsupported resolution %d", e.Resolution)
+}
+
+func (e UnsupportedResolutionError) Is(target error) bool {
+	return target == reader.ErrInvalidQuery
+}
+
+type UnsupportedAggregationError struct {
+	Aggregation models.Aggregation
+}
+
+func (e UnsupportedAggregationError) Error() string {
+	return fmt.Sprintf("unsupported aggregation %d", e.Aggregation)
+}
+
+func (e UnsupportedAggregationError) Is(target error) bool {
+	return target == reader.ErrInvalidQuery
+}
+
+type InvalidMatcherError struct {
+	Matcher models.LabelMatcher
+	Reason  string
+}
+
+func (e InvalidMatcherError) Error() string {
+	return fmt.Sprintf("invalid matcher on label '%s': %s", e.Matcher.Name, e.Reason)
+}
+
+func (e InvalidMatcherError) Is(target error) bool {
+	return target == reader.ErrInvalidQuery
+}
+
+// InvalidRegexError is returned when Postgres rejects the regular expression
+// of a matcher. Matchers are checked as RE2, which accepts constructs such
+// as (?i) in places Postgres does not.
+type InvalidRegexError struct {
+	Reason string
+}
+
+func (e InvalidRegexError) Error() string {
+	return fmt.Sprintf("matcher rejected by Postgres: %s", e.Reason)
+}
+
+func (e InvalidRegexError) Is(target error) bool {
+	return target == reader.ErrInvalidQuery
+}
+
+// invalidRegexCode is the SQLSTATE of invalid_regular_expression.
+const invalidRegexCode = "2201B"
+
+// queryError wraps an error of running query, unless Postgres rejected a
+// regular expression in it.
+func queryError(query string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == invalidRegexCode {
+		return InvalidRegexError{Reason: pgErr.Message}
+	}
+
+	return fmt.Errorf("failed to run query '%s': %w", query, err)
+}
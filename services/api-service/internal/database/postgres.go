@@ -11,7 +11,8 @@ import (
 )
 
 type Storage struct {
-	db *sql.DB
+	db        *sql.DB
+	timescale bool
 }
 
 func New(cfg config.PostgresConfig) (*Storage, error) {
@@ -37,7 +38,7 @@ func New(cfg config.PostgresConfig) (*Storage, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, timescale: cfg.Timescale}, nil
 }
 
 func (s *Storage) Close() error {
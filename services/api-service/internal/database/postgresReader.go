@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/reader"
@@ -19,11 +20,43 @@ func NewPostgresMetricsReeader(storage *Storage) reader.MetricsReader {
 	return &PostgresMetricsReader{storage: storage}
 }
 
-func (r *PostgresMetricsReader) GetMetrics(ctx context.Context, source, name string, limit int) ([]models.Metric, error) {
-	query := `SELECT source, name, value, labels, collected_at FROM metrics WHERE source = $1 AND name = $2 ORDER BY collected_at DESC LIMIT $3`
-	rows, err := r.storage.db.QueryContext(ctx, query, source, name, limit)
+// aggregateViews are the TimescaleDB continuous aggregates per resolution.
+var aggregateViews = map[models.Resolution]string{
+	models.ResolutionMinute: "metrics_1m",
+	models.ResolutionHour:   "metrics_1h",
+	models.ResolutionDay:    "metrics_1d",
+}
+
+// bucketWidths are the widths of the aggregate buckets, used to compute them
+// from raw rows without TimescaleDB.
+var bucketWidths = map[models.Resolution]string{
+	models.ResolutionMinute: "1 minute",
+	models.ResolutionHour:   "1 hour",
+	models.ResolutionDay:    "1 day",
+}
+
+// GetMetrics returns a page of samples, or of buckets at a coarser
+// resolution, newest first unless asked otherwise. Samples are read from the
+// partitions of the source and range only.
+func (r *PostgresMetricsReader) GetMetrics(ctx context.Context, q models.MetricsQuery) ([]models.Metric, *models.Cursor, error) {
+	if q.Resolution != models.ResolutionRaw {
+		return r.getAggregates(ctx, q)
+	}
+
+	var b queryBuilder
+	seriesFilter, err := b.seriesFilter(q)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run query '%s': %w", query, err)
+		return nil, nil, err
+	}
+	b.where(seriesFilter)
+	b.where("m.source = " + b.arg(q.Source))
+	orderBy := b.page(q, "m.collected_at", "m.id")
+
+	query := `SELECT m.id, s.source, s.name, m.value, s.labels, m.collected_at FROM metrics m JOIN series s ON s.id = m.series_id WHERE ` +
+		b.whereClause() + ` ` + orderBy
+	rows, err := r.storage.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, nil, queryError(query, err)
 	}
 	defer rows.Close()
 
@@ -32,7 +65,7 @@ func (r *PostgresMetricsReader) GetMetrics(ctx context.Context, source, name str
 	for rows.Next() {
 		var m models.Metric
 		var labelsJSON []byte
-		err := rows.Scan(&m.Source, &m.Name, &m.Value, &labelsJSON, &m.CollectedAt)
+		err := rows.Scan(&m.ID, &m.Source, &m.Name, &m.Value, &labelsJSON, &m.CollectedAt)
 		if err != nil {
 			mulSelErr.Errors = append(mulSelErr.Errors, fmt.Errorf("failed to scan row: %w", err))
 			mulSelErr.FailedCount++
@@ -49,15 +82,21 @@ func (r *PostgresMetricsReader) GetMetrics(ctx context.Context, source, name str
 		mulSelErr.SuccessfullCount++
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, nil, queryError(query, err)
+	}
+
+	metrics, next := nextCursor(metrics, q.Limit)
+
 	if len(mulSelErr.Errors) > 0 {
-		return metrics, &mulSelErr
+		return metrics, next, &mulSelErr
 	}
 
-	return metrics, nil
+	return metrics, next, nil
 }
 
 func (r *PostgresMetricsReader) GetMetric(ctx context.Context, source, name string) (*models.Metric, error) {
-	query := `SELECT source, name, value, labels, collected_at FROM metrics WHERE source = $1 AND name = $2 ORDER BY collected_at DESC LIMIT 1`
+	query := `SELECT s.source, s.name, m.value, s.labels, m.collected_at FROM metrics m JOIN series s ON s.id = m.series_id WHERE m.source = $1 AND s.source = $1 AND s.name = $2 ORDER BY m.collected_at DESC LIMIT 1`
 	var m models.Metric
 	var labelsJSON []byte
 
@@ -81,3 +120,196 @@ func (r *PostgresMetricsReader) GetMetric(ctx context.Context, source, name stri
 
 	return &m, nil
 }
+
+// getAggregates reads buckets from the continuous aggregate of the
+// resolution, joined with the series catalog for names and labels. Without
+// TimescaleDB they are computed from the raw rows of the series with
+// date_bin, which Postgres can only do by scanning them. Buckets are
+// selected by their start time; the cursor ID is the series ID.
+func (r *PostgresMetricsReader) getAggregates(ctx context.Context, q models.MetricsQuery) ([]models.Metric, *models.Cursor, error) {
+	from, ok := aggregateViews[q.Resolution]
+	if !ok {
+		return nil, nil, UnsupportedResolutionError{Resolution: q.Resolution}
+	}
+
+	var b queryBuilder
+	seriesFilter, err := b.seriesFilter(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.where(seriesFilter)
+
+	if !r.storage.timescale {
+		width := bucketWidths[q.Resolution]
+
+		// The raw rows are limited to the source's partitions and the
+		// buckets of the range before being grouped.
+		rawFilter := []string{"source = " + b.arg(q.Source), "series_id IN (SELECT s.id FROM series s WHERE " + seriesFilter + ")"}
+		if !q.Start.IsZero() {
+			rawFilter = append(rawFilter, fmt.Sprintf("collected_at >= date_bin(INTERVAL '%s', %s::timestamptz, TIMESTAMPTZ '2000-01-01')", width, b.arg(q.Start)))
+		}
+		if !q.End.IsZero() {
+			rawFilter = append(rawFilter, "collected_at < "+b.arg(q.End))
+		}
+
+		from = fmt.Sprintf(`(
+			SELECT
+				date_bin(INTERVAL '%s', collected_at, TIMESTAMPTZ '2000-01-01') AS bucket,
+				series_id,
+				avg(value) AS avg_value,
+				min(value) AS min_value,
+				max(value) AS max_value,
+				count(*) AS sample_count
+			FROM metrics
+			WHERE %s
+			GROUP BY bucket, series_id
+		)`, width, strings.Join(rawFilter, " AND "))
+	}
+
+	orderBy := b.page(q, "a.bucket", "a.series_id")
+
+	query := `SELECT a.series_id, s.source, s.name, a.avg_value, a.min_value, a.max_value, a.sample_count, s.labels, a.bucket FROM ` + from +
+		` a JOIN series s ON s.id = a.series_id WHERE ` + b.whereClause() + ` ` + orderBy
+	rows, err := r.storage.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, nil, queryError(query, err)
+	}
+	defer rows.Close()
+
+	var metrics []models.Metric
+	var mulSelErr MultipleSelectError
+	for rows.Next() {
+		m := models.Metric{Aggregate: &models.Aggregate{}}
+		var labelsJSON []byte
+		err := rows.Scan(&m.ID, &m.Source, &m.Name, &m.Value, &m.Aggregate.Min, &m.Aggregate.Max, &m.Aggregate.Count, &labelsJSON, &m.CollectedAt)
+		if err != nil {
+			mulSelErr.Errors = append(mulSelErr.Errors, fmt.Errorf("failed to scan row: %w", err))
+			mulSelErr.FailedCount++
+			continue
+		}
+
+		if err := json.Unmarshal(labelsJSON, &m.Labels); err != nil {
+			mulSelErr.Errors = append(mulSelErr.Errors, fmt.Errorf("failed to unmarshal labels '%v': %w", labelsJSON, err))
+			mulSelErr.FailedCount++
+			continue
+		}
+
+		metrics = append(metrics, m)
+		mulSelErr.SuccessfullCount++
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, queryError(query, err)
+	}
+
+	metrics, next := nextCursor(metrics, q.Limit)
+
+	if len(mulSelErr.Errors) > 0 {
+		return metrics, next, &mulSelErr
+	}
+
+	return metrics, next, nil
+}
+
+// defaultSeriesLimit caps series listings that do not set a limit.
+const defaultSeriesLimit = 1000
+
+func (r *PostgresMetricsReader) ListSeries(ctx context.Context, source, name string, limit int) ([]models.Series, error) {
+	if limit <= 0 {
+		limit = defaultSeriesLimit
+	}
+
+	query := `SELECT id, source, name, labels, COALESCE(unit, ''), COALESCE(description, ''), first_seen, last_seen FROM series WHERE ($1 = '' OR source = $1) AND ($2 = '' OR name = $2) ORDER BY source, name, id LIMIT $3`
+	rows, err := r.storage.db.QueryContext(ctx, query, source, name, limit)
+	if err != nil {
+		return nil, queryError(query, err)
+	}
+	defer rows.Close()
+
+	var series []models.Series
+	var mulSelErr MultipleSelectError
+	for rows.Next() {
+		var s models.Series
+		var labelsJSON []byte
+		err := rows.Scan(&s.ID, &s.Source, &s.Name, &labelsJSON, &s.Unit, &s.Description, &s.FirstSeen, &s.LastSeen)
+		if err != nil {
+			mulSelErr.Errors = append(mulSelErr.Errors, fmt.Errorf("failed to scan row: %w", err))
+			mulSelErr.FailedCount++
+			continue
+		}
+
+		if err := json.Unmarshal(labelsJSON, &s.Labels); err != nil {
+			mulSelErr.Errors = append(mulSelErr.Errors, fmt.Errorf("failed to unmarshal labels '%v': %w", labelsJSON, err))
+			mulSelErr.FailedCount++
+			continue
+		}
+
+		series = append(series, s)
+		mulSelErr.SuccessfullCount++
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(query, err)
+	}
+
+	if len(mulSelErr.Errors) > 0 {
+		return series, &mulSelErr
+	}
+
+	return series, nil
+}
+
+func (r *PostgresMetricsReader) ListSources(ctx context.Context, q models.SeriesQuery) ([]string, error) {
+	return r.listCatalog(ctx, &queryBuilder{}, q, "s.source")
+}
+
+func (r *PostgresMetricsReader) ListMetricNames(ctx context.Context, q models.SeriesQuery) ([]string, error) {
+	return r.listCatalog(ctx, &queryBuilder{}, q, "s.name")
+}
+
+func (r *PostgresMetricsReader) ListLabelKeys(ctx context.Context, q models.SeriesQuery) ([]string, error) {
+	return r.listCatalog(ctx, &queryBuilder{}, q, "jsonb_object_keys(s.labels)")
+}
+
+// ListLabelValues returns the values of the label. Series without the label
+// are skipped using the GIN index on labels.
+func (r *PostgresMetricsReader) ListLabelValues(ctx context.Context, q models.SeriesQuery, label string) ([]string, error) {
+	var b queryBuilder
+	key := b.arg(label)
+	b.where("s.labels ? " + key + "::text")
+
+	return r.listCatalog(ctx, &b, q, "s.labels->>"+key+"::text")
+}
+
+// listCatalog returns the distinct values of column across the series the
+// query selects, in sorted order.
+func (r *PostgresMetricsReader) listCatalog(ctx context.Context, b *queryBuilder, q models.SeriesQuery, column string) ([]string, error) {
+	filter, err := b.catalogFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	b.where(filter)
+
+	query := `SELECT DISTINCT ` + column + ` FROM series s WHERE ` + b.whereClause() + ` ORDER BY 1`
+	rows, err := r.storage.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, queryError(query, err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		values = append(values, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(query, err)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+)
+
+const (
+	defaultMetricsLimit = 100
+	maxMetricsLimit     = 10000
+)
+
+// queryBuilder collects the conditions of a query together with the
+// arguments they refer to, numbering the placeholders as they are added.
+type queryBuilder struct {
+	conds []string
+	args  []any
+}
+
+func (b *queryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+func (b *queryBuilder) whereClause() string {
+	return strings.Join(b.conds, " AND ")
+}
+
+// seriesFilter returns the condition on the series catalog, aliased s, that
+// selects the series of the query.
+func (b *queryBuilder) seriesFilter(q models.MetricsQuery) (string, error) {
+	conds := []string{
+		"s.source = " + b.arg(q.Source),
+		"s.name = " + b.arg(q.Name),
+	}
+
+	labelConds, err := b.labelFilter(q.Matchers)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(append(conds, labelConds...), " AND "), nil
+}
+
+// catalogFilter is seriesFilter for catalog lookups, where source and name
+// are optional. The range is checked against first_seen and last_seen, so a
+// series may match up to a minute past its last sample.
+func (b *queryBuilder) catalogFilter(q models.SeriesQuery) (string, error) {
+	var conds []string
+	if q.Source != "" {
+		conds = append(conds, "s.source = "+b.arg(q.Source))
+	}
+	if q.Name != "" {
+		conds = append(conds, "s.name = "+b.arg(q.Name))
+	}
+	if !q.Start.IsZero() {
+		conds = append(conds, "s.last_seen >= "+b.arg(q.Start))
+	}
+	if !q.End.IsZero() {
+		conds = append(conds, "s.first_seen < "+b.arg(q.End))
+	}
+
+	labelConds, err := b.labelFilter(q.Matchers)
+	if err != nil {
+		return "", err
+	}
+
+	conds = append(conds, labelConds...)
+	if len(conds) == 0 {
+		return "TRUE", nil
+	}
+
+	return strings.Join(conds, " AND "), nil
+}
+
+// labelFilter returns the conditions of the matchers on the labels of the
+// series catalog. Equality matchers use containment, which the GIN index on
+// labels serves; the other matchers are checked on the series left after
+// that.
+func (b *queryBuilder) labelFilter(matchers []models.LabelMatcher) ([]string, error) {
+	var conds []string
+	for _, m := range matchers {
+		if m.Name == "" {
+			return nil, InvalidMatcherError{Matcher: m, Reason: "empty label name"}
+		}
+
+		if m.Type == models.MatchEqual && m.Value != "" {
+			contained, err := json.Marshal(map[string]string{m.Name: m.Value})
+			if err != nil {
+				return nil, InvalidMatcherError{Matcher: m, Reason: err.Error()}
+			}
+			conds = append(conds, "s.labels @> "+b.arg(string(contained))+"::jsonb")
+			continue
+		}
+
+		value := fmt.Sprintf("COALESCE(s.labels->>%s::text, '')", b.arg(m.Name))
+
+		switch m.Type {
+		case models.MatchEqual:
+			conds = append(conds, value+" = ''")
+		case models.MatchNotEqual:
+			conds = append(conds, value+" <> "+b.arg(m.Value))
+		case models.MatchRegex:
+			conds = append(conds, value+" ~ "+b.arg("^(?:"+m.Value+")$"))
+		case models.MatchNotRegex:
+			conds = append(conds, value+" !~ "+b.arg("^(?:"+m.Value+")$"))
+		default:
+			return nil, InvalidMatcherError{Matcher: m, Reason: "unknown match type"}
+		}
+	}
+
+	return conds, nil
+}
+
+// page adds the time range and the cursor on the time and ID columns and
+// returns the ORDER BY and LIMIT clause. One row more than the limit is
+// asked for, to tell whether another page follows.
+func (b *queryBuilder) page(q models.MetricsQuery, timeCol, idCol string) string {
+	if !q.Start.IsZero() {
+		b.where(timeCol + " >= " + b.arg(q.Start))
+	}
+	if !q.End.IsZero() {
+		b.where(timeCol + " < " + b.arg(q.End))
+	}
+
+	dir, cmp := "DESC", "<"
+	if q.Order == models.OrderOldestFirst {
+		dir, cmp = "ASC", ">"
+	}
+
+	if q.After != nil {
+		b.where(fmt.Sprintf("(%s, %s) %s (%s, %s)", timeCol, idCol, cmp, b.arg(q.After.Time), b.arg(q.After.ID)))
+	}
+
+	return fmt.Sprintf("ORDER BY %s %s, %s %s LIMIT %s", timeCol, dir, idCol, dir, b.arg(pageLimit(q.Limit)+1))
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultMetricsLimit
+	}
+
+	return min(limit, maxMetricsLimit)
+}
+
+// nextCursor trims the extra row asked for by page and returns the cursor
+// of the last metric kept, or nil if there is no further page.
+func nextCursor(metrics []models.Metric, limit int) ([]models.Metric, *models.Cursor) {
+	limit = pageLimit(limit)
+	if len(metrics) <= limit {
+		return metrics, nil
+	}
+
+	metrics = metrics[:limit]
+	last := metrics[limit-1]
+
+	return metrics, &models.Cursor{Time: last.CollectedAt, ID: last.ID}
+}
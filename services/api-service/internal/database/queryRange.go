@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+)
+
+// rangeAggregations compute each aggregation from raw samples.
+var rangeAggregations = map[models.Aggregation]string{
+	models.AggregationAvg:   "avg(value)",
+	models.AggregationMin:   "min(value)",
+	models.AggregationMax:   "max(value)",
+	models.AggregationSum:   "sum(value)",
+	models.AggregationCount: "count(*)::double precision",
+	models.AggregationLast:  "(array_agg(value ORDER BY collected_at DESC))[1]",
+	models.AggregationP50:   "percentile_cont(0.5) WITHIN GROUP (ORDER BY value)",
+	models.AggregationP95:   "percentile_cont(0.95) WITHIN GROUP (ORDER BY value)",
+	models.AggregationP99:   "percentile_cont(0.99) WITHIN GROUP (ORDER BY value)",
+}
+
+// rollupAggregations compute the aggregations that can be combined from the
+// buckets of a continuous aggregate.
+var rollupAggregations = map[models.Aggregation]string{
+	models.AggregationAvg:   "sum(avg_value * sample_count) / sum(sample_count)",
+	models.AggregationMin:   "min(min_value)",
+	models.AggregationMax:   "max(max_value)",
+	models.AggregationSum:   "sum(avg_value * sample_count)",
+	models.AggregationCount: "sum(sample_count)::double precision",
+}
+
+// rollupWidths are the bucket widths of the continuous aggregates, coarsest
+// first.
+var rollupWidths = []struct {
+	resolution models.Resolution
+	width      time.Duration
+}{
+	{models.ResolutionDay, 24 * time.Hour},
+	{models.ResolutionHour, time.Hour},
+	{models.ResolutionMinute, time.Minute},
+}
+
+// QueryRange aggregates the samples of each step with date_bin, so only one
+// row per series and step leaves the database. With TimescaleDB, steps made
+// of whole buckets of a continuous aggregate are combined from its buckets
+// instead of the raw samples, unless the aggregation needs every sample.
+func (r *PostgresMetricsReader) QueryRange(ctx context.Context, q models.RangeQuery) ([]models.RangeSeries, error) {
+	value, ok := rangeAggregations[q.Aggregation]
+	if !ok {
+		return nil, UnsupportedAggregationError{Aggregation: q.Aggregation}
+	}
+
+	var b queryBuilder
+	step := b.arg(fmt.Sprintf("%d microseconds", q.Step.Microseconds()))
+	start := b.arg(q.Start)
+
+	seriesFilter, err := b.seriesFilter(models.MetricsQuery{Source: q.Source, Name: q.Name, Matchers: q.Matchers})
+	if err != nil {
+		return nil, err
+	}
+	b.where("series_id IN (SELECT s.id FROM series s WHERE " + seriesFilter + ")")
+
+	from, timeCol := "metrics", "collected_at"
+	if view, ok := r.rollupView(q); ok {
+		from, timeCol, value = view, "bucket", rollupAggregations[q.Aggregation]
+	} else {
+		b.where("source = " + b.arg(q.Source))
+	}
+	b.where(timeCol + " >= " + start)
+	b.where(timeCol + " < " + b.arg(q.End))
+
+	query := fmt.Sprintf(`SELECT a.series_id, s.source, s.name, s.labels, a.step_start, a.value FROM (
+			SELECT
+				date_bin(%s::interval, %s, %s::timestamptz) AS step_start,
+				series_id,
+				%s AS value
+			FROM %s
+			WHERE %s
+			GROUP BY step_start, series_id
+		) a JOIN series s ON s.id = a.series_id ORDER BY a.series_id, a.step_start`,
+		step, timeCol, start, value, from, b.whereClause())
+	rows, err := r.storage.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, queryError(query, err)
+	}
+	defer rows.Close()
+
+	var series []models.RangeSeries
+	for rows.Next() {
+		var s models.RangeSeries
+		var labelsJSON []byte
+		var p models.Point
+		if err := rows.Scan(&s.ID, &s.Source, &s.Name, &labelsJSON, &p.Time, &p.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if len(series) == 0 || series[len(series)-1].ID != s.ID {
+			if err := json.Unmarshal(labelsJSON, &s.Labels); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal labels '%v': %w", labelsJSON, err)
+			}
+			series = append(series, s)
+		}
+
+		last := &series[len(series)-1]
+		last.Points = append(last.Points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, queryError(query, err)
+	}
+
+	return series, nil
+}
+
+// rollupView returns the coarsest continuous aggregate whose buckets fit the
+// steps of the query exactly: the step is a multiple of the bucket width and
+// the range starts and ends on bucket boundaries.
+func (r *PostgresMetricsReader) rollupView(q models.RangeQuery) (string, bool) {
+	if !r.storage.timescale {
+		return "", false
+	}
+	if _, ok := rollupAggregations[q.Aggregation]; !ok {
+		return "", false
+	}
+
+	for _, w := range rollupWidths {
+		if q.Step%w.width == 0 && q.Start.Truncate(w.width).Equal(q.Start) && q.End.Truncate(w.width).Equal(q.End) {
+			return aggregateViews[w.resolution], true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/proto"
+)
+
+const (
+	minRangeStep = time.Second
+	// maxRangeSteps is the number of steps Prometheus allows in a range
+	// query.
+	maxRangeSteps = 11000
+)
+
+// metricsQuery validates the request and converts it to a query.
+func metricsQuery(req *proto.GetMetricsRequest) (models.MetricsQuery, error) {
+	q := models.MetricsQuery{
+		Source:     req.Source,
+		Name:       req.Name,
+		Resolution: models.Resolution(req.Resolution),
+		Order:      models.Order(req.Order),
+		Limit:      int(req.Limit),
+	}
+
+	if _, ok := proto.Resolution_name[int32(req.Resolution)]; !ok {
+		return q, fmt.Errorf("unknown resolution %d", req.Resolution)
+	}
+
+	var err error
+	if q.Start, err = parseTime(req.Start); err != nil {
+		return q, fmt.Errorf("invalid start: %w", err)
+	}
+	if q.End, err = parseTime(req.End); err != nil {
+		return q, fmt.Errorf("invalid end: %w", err)
+	}
+	if !q.Start.IsZero() && !q.End.IsZero() && !q.Start.Before(q.End) {
+		return q, fmt.Errorf("start %s is not before end %s", req.Start, req.End)
+	}
+
+	if q.Matchers, err = labelMatchers(req.Matchers); err != nil {
+		return q, err
+	}
+
+	if req.PageToken != "" {
+		cursor, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return q, err
+		}
+		q.After = &cursor
+	}
+
+	return q, nil
+}
+
+// rangeQuery validates the request and converts it to a query. Start, end
+// and step are required, and the step must leave at most maxRangeSteps
+// steps in the range.
+func rangeQuery(req *proto.QueryRangeRequest) (models.RangeQuery, error) {
+	q := models.RangeQuery{
+		Source:      req.Source,
+		Name:        req.Name,
+		Aggregation: models.Aggregation(req.Aggregation),
+	}
+
+	if _, ok := proto.Aggregation_name[int32(req.Aggregation)]; !ok {
+		return q, fmt.Errorf("unknown aggregation %d", req.Aggregation)
+	}
+
+	if req.Start == "" || req.End == "" {
+		return q, fmt.Errorf("start and end are required")
+	}
+
+	var err error
+	if q.Start, err = parseTime(req.Start); err != nil {
+		return q, fmt.Errorf("invalid start: %w", err)
+	}
+	if q.End, err = parseTime(req.End); err != nil {
+		return q, fmt.Errorf("invalid end: %w", err)
+	}
+	if !q.Start.Before(q.End) {
+		return q, fmt.Errorf("start %s is not before end %s", req.Start, req.End)
+	}
+
+	if q.Step, err = time.ParseDuration(req.Step); err != nil {
+		return q, fmt.Errorf("invalid step: %w", err)
+	}
+	if q.Step < minRangeStep {
+		return q, fmt.Errorf("step %s is shorter than %s", q.Step, minRangeStep)
+	}
+	if steps := q.End.Sub(q.Start) / q.Step; steps > maxRangeSteps {
+		return q, fmt.Errorf("range of %d steps exceeds the maximum of %d, use a longer step", steps, maxRangeSteps)
+	}
+
+	if q.Matchers, err = labelMatchers(req.Matchers); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// seriesQuery validates the selector of a catalog lookup and converts it to
+// a query.
+func seriesQuery(source, name string, matchers []*proto.LabelMatcher, start, end string) (models.SeriesQuery, error) {
+	q := models.SeriesQuery{
+		Source: source,
+		Name:   name,
+	}
+
+	var err error
+	if q.Start, err = parseTime(start); err != nil {
+		return q, fmt.Errorf("invalid start: %w", err)
+	}
+	if q.End, err = parseTime(end); err != nil {
+		return q, fmt.Errorf("invalid end: %w", err)
+	}
+	if !q.Start.IsZero() && !q.End.IsZero() && !q.Start.Before(q.End) {
+		return q, fmt.Errorf("start %s is not before end %s", start, end)
+	}
+
+	if q.Matchers, err = labelMatchers(matchers); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// labelMatchers converts matchers. Regular expressions must be valid RE2, as
+// in Prometheus, although Postgres evaluates them; those Postgres rejects
+// fail the query with InvalidArgument too.
+func labelMatchers(matchers []*proto.LabelMatcher) ([]models.LabelMatcher, error) {
+	var result []models.LabelMatcher
+	for _, m := range matchers {
+		if m.Name == "" {
+			return nil, fmt.Errorf("matcher without label name")
+		}
+
+		matcher := models.LabelMatcher{Name: m.Name, Value: m.Value}
+		switch m.Type {
+		case proto.LabelMatcher_EQUAL:
+			matcher.Type = models.MatchEqual
+		case proto.LabelMatcher_NOT_EQUAL:
+			matcher.Type = models.MatchNotEqual
+		case proto.LabelMatcher_REGEX, proto.LabelMatcher_NOT_REGEX:
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return nil, fmt.Errorf("invalid regular expression for label %s: %w", m.Name, err)
+			}
+
+			matcher.Type = models.MatchRegex
+			if m.Type == proto.LabelMatcher_NOT_REGEX {
+				matcher.Type = models.MatchNotRegex
+			}
+		default:
+			return nil, fmt.Errorf("unknown match type %d for label %s", m.Type, m.Name)
+		}
+
+		result = append(result, matcher)
+	}
+
+	return result, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// Page tokens are opaque to clients; they hold the cursor as
+// "<unix nanoseconds>:<id>".
+func encodePageToken(cursor *models.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.FormatInt(cursor.Time.UnixNano(), 10) + ":" + strconv.FormatInt(cursor.ID, 10)),
+	)
+}
+
+func decodePageToken(token string) (models.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.Cursor{}, fmt.Errorf("invalid page token: %w", err)
+	}
+
+	nanosStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return models.Cursor{}, fmt.Errorf("invalid page token")
+	}
+
+	nanos, err := strconv.ParseInt(nanosStr, 10, 64)
+	if err != nil {
+		return models.Cursor{}, fmt.Errorf("invalid page token: %w", err)
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return models.Cursor{}, fmt.Errorf("invalid page token: %w", err)
+	}
+
+	return models.Cursor{Time: time.Unix(0, nanos), ID: id}, nil
+}
@@ -2,23 +2,32 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/subscription"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/reader"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
 	proto.UnimplementedMetricsServiceServer
 	reader  reader.MetricsReader
+	hub     *subscription.Hub
 	metrics *metrics.Metrics
 }
 
-func NewServer(reader reader.MetricsReader, metrics *metrics.Metrics) *Server {
+// NewServer creates the gRPC server. hub may be nil, in which case
+// Subscribe is unavailable.
+func NewServer(reader reader.MetricsReader, hub *subscription.Hub, metrics *metrics.Metrics) *Server {
 	return &Server{
 		reader:  reader,
+		hub:     hub,
 		metrics: metrics,
 	}
 }
@@ -33,32 +42,27 @@ func (s *Server) GetMetrics(ctx context.Context, req *proto.GetMetricsRequest) (
 		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
 	}()
 
-	metrics, err := s.reader.GetMetrics(ctx, req.Source, req.Name, int(req.Limit))
+	query, err := metricsQuery(req)
 	if err != nil {
 		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
-		return nil, fmt.Errorf("failed to get metrics: %w", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	metrics, next, err := s.reader.GetMetrics(ctx, query)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, readerError(err, "failed to get metrics")
 	}
 
 	protoMetrics := make([]*proto.Metric, 0, len(metrics))
 
 	for _, m := range metrics {
-		labels := make(map[string]string)
-
-		for k, v := range m.Labels {
-			labels[k] = v.(string)
-		}
-
-		protoMetrics = append(protoMetrics, &proto.Metric{
-			Source:      m.Source,
-			Name:        m.Name,
-			Value:       m.Value,
-			Labels:      labels,
-			CollectedAt: m.CollectedAt.Format(time.RFC3339),
-		})
+		protoMetrics = append(protoMetrics, protoMetric(m))
 	}
 
 	return &proto.GetMetricsResponse{
-		Metrics: protoMetrics,
+		Metrics:       protoMetrics,
+		NextPageToken: encodePageToken(next),
 	}, nil
 }
 
@@ -82,18 +86,298 @@ func (s *Server) GetMetric(ctx context.Context, req *proto.GetMetricRequest) (*p
 		return &proto.GetMetricResponse{Metric: nil}, nil
 	}
 
-	labels := make(map[string]string)
-	for k, v := range metric.Labels {
-		labels[k] = v.(string)
+	return &proto.GetMetricResponse{
+		Metric: protoMetric(*metric),
+	}, nil
+}
+
+func (s *Server) ListSeries(ctx context.Context, req *proto.ListSeriesRequest) (*proto.ListSeriesResponse, error) {
+	methodName := "ListSeries"
+	start := time.Now()
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
+	}()
+
+	series, err := s.reader.ListSeries(ctx, req.Source, req.Name, int(req.Limit))
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+
+	protoSeries := make([]*proto.Series, 0, len(series))
+	for _, ser := range series {
+		protoSeries = append(protoSeries, &proto.Series{
+			Id:          ser.ID,
+			Source:      ser.Source,
+			Name:        ser.Name,
+			Labels:      protoLabels(ser.Labels),
+			Unit:        ser.Unit,
+			Description: ser.Description,
+			FirstSeen:   ser.FirstSeen.Format(time.RFC3339Nano),
+			LastSeen:    ser.LastSeen.Format(time.RFC3339Nano),
+		})
 	}
 
-	return &proto.GetMetricResponse{
-		Metric: &proto.Metric{
-			Source:      metric.Source,
-			Name:        metric.Name,
-			Value:       metric.Value,
-			Labels:      labels,
-			CollectedAt: metric.CollectedAt.Format(time.RFC3339),
-		},
+	return &proto.ListSeriesResponse{
+		Series: protoSeries,
+	}, nil
+}
+
+func (s *Server) ListSources(ctx context.Context, req *proto.ListSourcesRequest) (*proto.ListSourcesResponse, error) {
+	methodName := "ListSources"
+	start := time.Now()
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
+	}()
+
+	query, err := seriesQuery("", req.Name, req.Matchers, req.Start, req.End)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sources, err := s.reader.ListSources(ctx, query)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, readerError(err, "failed to list sources")
+	}
+
+	return &proto.ListSourcesResponse{
+		Sources: sources,
+	}, nil
+}
+
+func (s *Server) ListMetricNames(ctx context.Context, req *proto.ListMetricNamesRequest) (*proto.ListMetricNamesResponse, error) {
+	methodName := "ListMetricNames"
+	start := time.Now()
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
+	}()
+
+	query, err := seriesQuery(req.Source, "", req.Matchers, req.Start, req.End)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	names, err := s.reader.ListMetricNames(ctx, query)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, readerError(err, "failed to list metric names")
+	}
+
+	return &proto.ListMetricNamesResponse{
+		Names: names,
+	}, nil
+}
+
+func (s *Server) ListLabelKeys(ctx context.Context, req *proto.ListLabelKeysRequest) (*proto.ListLabelKeysResponse, error) {
+	methodName := "ListLabelKeys"
+	start := time.Now()
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
+	}()
+
+	query, err := seriesQuery(req.Source, req.Name, req.Matchers, req.Start, req.End)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	keys, err := s.reader.ListLabelKeys(ctx, query)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, readerError(err, "failed to list label keys")
+	}
+
+	return &proto.ListLabelKeysResponse{
+		Keys: keys,
+	}, nil
+}
+
+func (s *Server) ListLabelValues(ctx context.Context, req *proto.ListLabelValuesRequest) (*proto.ListLabelValuesResponse, error) {
+	methodName := "ListLabelValues"
+	start := time.Now()
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
+	}()
+
+	if req.Label == "" {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, status.Error(codes.InvalidArgument, "label is required")
+	}
+
+	query, err := seriesQuery(req.Source, req.Name, req.Matchers, req.Start, req.End)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	values, err := s.reader.ListLabelValues(ctx, query, req.Label)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, readerError(err, "failed to list label values")
+	}
+
+	return &proto.ListLabelValuesResponse{
+		Values: values,
+	}, nil
+}
+
+func (s *Server) QueryRange(ctx context.Context, req *proto.QueryRangeRequest) (*proto.QueryRangeResponse, error) {
+	methodName := "QueryRange"
+	start := time.Now()
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		s.metrics.RequestDuration.WithLabelValues(methodName).Observe(duration)
+	}()
+
+	query, err := rangeQuery(req)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	series, err := s.reader.QueryRange(ctx, query)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return nil, readerError(err, "failed to query range")
+	}
+
+	protoSeries := make([]*proto.RangeSeries, 0, len(series))
+	for _, ser := range series {
+		points := make([]*proto.Point, 0, len(ser.Points))
+		for _, p := range ser.Points {
+			points = append(points, &proto.Point{
+				Time:  p.Time.Format(time.RFC3339Nano),
+				Value: p.Value,
+			})
+		}
+
+		protoSeries = append(protoSeries, &proto.RangeSeries{
+			Id:     ser.ID,
+			Source: ser.Source,
+			Name:   ser.Name,
+			Labels: protoLabels(ser.Labels),
+			Points: points,
+		})
+	}
+
+	return &proto.QueryRangeResponse{
+		Series: protoSeries,
 	}, nil
 }
+
+// Subscribe streams the metrics the request selects until the client goes
+// away or the server shuts down. Metrics a slow client cannot take are
+// dropped and reported with the next one it receives.
+func (s *Server) Subscribe(req *proto.SubscribeRequest, stream proto.MetricsService_SubscribeServer) error {
+	methodName := "Subscribe"
+
+	s.metrics.RequestsTotal.WithLabelValues(methodName).Inc()
+
+	if s.hub == nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return status.Error(codes.Unimplemented, "subscriptions are disabled")
+	}
+
+	matchers, err := labelMatchers(req.Matchers)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	selector, err := subscription.NewSelector(req.Source, req.Name, matchers)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := s.hub.Subscribe(selector)
+	if err != nil {
+		s.metrics.RequestsFailedTotal.WithLabelValues(methodName).Inc()
+
+		var tooMany subscription.TooManySubscribersError
+		if errors.As(err, &tooMany) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer sub.Close()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+
+			if err := stream.Send(&proto.SubscribeResponse{
+				Metric:  protoMetric(update.Metric),
+				Dropped: update.Dropped,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func protoMetric(m models.Metric) *proto.Metric {
+	protoMetric := &proto.Metric{
+		Source:      m.Source,
+		Name:        m.Name,
+		Value:       m.Value,
+		Labels:      protoLabels(m.Labels),
+		CollectedAt: m.CollectedAt.Format(time.RFC3339Nano),
+	}
+	if m.Aggregate != nil {
+		protoMetric.Aggregate = &proto.Aggregate{
+			Min:   m.Aggregate.Min,
+			Max:   m.Aggregate.Max,
+			Count: m.Aggregate.Count,
+		}
+	}
+
+	return protoMetric
+}
+
+// protoLabels formats label values as strings; labels stored as JSON may
+// also hold numbers and booleans.
+func protoLabels(labels map[string]any) map[string]string {
+	protoLabels := make(map[string]string, len(labels))
+	for k, v := range labels {
+		protoLabels[k] = fmt.Sprint(v)
+	}
+
+	return protoLabels
+}
+
+// readerError converts an error of the reader to a status: queries the
+// reader cannot run are the client's fault.
+func readerError(err error, msg string) error {
+	if errors.Is(err, reader.ErrInvalidQuery) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -9,6 +9,11 @@ type Metrics struct {
 	RequestsTotal       *prometheus.CounterVec
 	RequestsFailedTotal *prometheus.CounterVec
 	RequestDuration     *prometheus.HistogramVec
+
+	Subscribers                    prometheus.Gauge
+	SubscriptionMetricsTotal       prometheus.Counter
+	SubscriptionDroppedTotal       prometheus.Counter
+	SubscriptionConsumeErrorsTotal prometheus.Counter
 }
 
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -27,5 +32,21 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Help:    "Duration of gRPC requests",
 			Buckets: prometheus.DefBuckets,
 		}, []string{"method"}),
+		Subscribers: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "api_service_subscribers",
+			Help: "Number of open Subscribe streams",
+		}),
+		SubscriptionMetricsTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "api_service_subscription_metrics_total",
+			Help: "Total number of metrics consumed for subscriptions",
+		}),
+		SubscriptionDroppedTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "api_service_subscription_dropped_total",
+			Help: "Total number of metrics dropped for subscribers whose buffer was full",
+		}),
+		SubscriptionConsumeErrorsTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "api_service_subscription_consume_errors_total",
+			Help: "Total number of errors consuming metrics for subscriptions",
+		}),
 	}
 }
@@ -0,0 +1,17 @@
+package subscription
+
+import "fmt"
+
+type TooManySubscribersError struct {
+	Max int
+}
+
+func (e TooManySubscribersError) Error() string {
+	return fmt.Sprintf("too many subscribers, at most %d are allowed", e.Max)
+}
+
+type ClosedError struct{}
+
+func (e ClosedError) Error() string {
+	return "subscriptions are closed"
+}
@@ -0,0 +1,181 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+)
+
+const (
+	defaultBufferSize     = 256
+	defaultMaxSubscribers = 100
+
+	// consumeBackoff is waited after the consumer fails, so a broker that is
+	// down is not polled in a tight loop.
+	consumeBackoff = time.Second
+)
+
+// Update is a metric for a subscriber together with the number of matching
+// metrics dropped for it since the previous update.
+type Update struct {
+	Metric  models.Metric
+	Dropped int64
+}
+
+// Hub consumes metrics once and fans them out to the subscribers they
+// match. Every subscriber has a bounded buffer; while it is full, metrics
+// for that subscriber are dropped and counted, so a slow client holds up
+// neither the consumer nor the other clients.
+type Hub struct {
+	consumer consumer.MessageConsumer
+	log      logger.Logger
+	metrics  *metrics.Metrics
+
+	bufferSize     int
+	maxSubscribers int
+
+	mu          sync.Mutex
+	subscribers map[*Subscriber]struct{}
+	closed      bool
+}
+
+func NewHub(consumer consumer.MessageConsumer, log logger.Logger, metrics *metrics.Metrics, cfg config.SubscriptionsConfig) *Hub {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.MaxSubscribers <= 0 {
+		cfg.MaxSubscribers = defaultMaxSubscribers
+	}
+
+	return &Hub{
+		consumer:       consumer,
+		log:            log,
+		metrics:        metrics,
+		bufferSize:     cfg.BufferSize,
+		maxSubscribers: cfg.MaxSubscribers,
+		subscribers:    make(map[*Subscriber]struct{}),
+	}
+}
+
+// Subscriber receives the metrics its selector matches until it is closed
+// or the hub stops.
+type Subscriber struct {
+	hub      *Hub
+	selector Selector
+	updates  chan Update
+
+	// dropped is only touched by the hub while it holds its lock.
+	dropped int64
+}
+
+// Updates is closed when the hub stops.
+func (s *Subscriber) Updates() <-chan Update {
+	return s.updates
+}
+
+func (s *Subscriber) Close() {
+	s.hub.remove(s)
+}
+
+func (h *Hub) Subscribe(selector Selector) (*Subscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ClosedError{}
+	}
+	if len(h.subscribers) >= h.maxSubscribers {
+		return nil, TooManySubscribersError{Max: h.maxSubscribers}
+	}
+
+	s := &Subscriber{
+		hub:      h,
+		selector: selector,
+		updates:  make(chan Update, h.bufferSize),
+	}
+	h.subscribers[s] = struct{}{}
+	h.metrics.Subscribers.Inc()
+
+	return s, nil
+}
+
+// Run consumes metrics until ctx is cancelled, then closes every
+// subscriber. Metrics that fail to decode are skipped.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+
+	h.log.Info("starting subscriptions", "buffer_size", h.bufferSize, "max_subscribers", h.maxSubscribers)
+
+	for {
+		metric, err := h.consumer.FetchMetric(ctx)
+
+		var decodeErr consumer.DecodeError
+		switch {
+		case ctx.Err() != nil:
+			h.log.Info("subscriptions stopped")
+			return
+		case errors.As(err, &decodeErr):
+			h.metrics.SubscriptionConsumeErrorsTotal.Inc()
+			h.log.Warn("skipping metric that failed to decode", "error", err)
+		case err != nil:
+			h.metrics.SubscriptionConsumeErrorsTotal.Inc()
+			h.log.Error("failed to consume metric for subscriptions", "error", err)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(consumeBackoff):
+			}
+		default:
+			h.metrics.SubscriptionMetricsTotal.Inc()
+			h.publish(metric)
+		}
+	}
+}
+
+func (h *Hub) publish(metric models.Metric) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscribers {
+		if !s.selector.Matches(metric) {
+			continue
+		}
+
+		select {
+		case s.updates <- Update{Metric: metric, Dropped: s.dropped}:
+			s.dropped = 0
+		default:
+			s.dropped++
+			h.metrics.SubscriptionDroppedTotal.Inc()
+		}
+	}
+}
+
+func (h *Hub) remove(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		h.metrics.Subscribers.Dec()
+	}
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for s := range h.subscribers {
+		close(s.updates)
+		delete(h.subscribers, s)
+		h.metrics.Subscribers.Dec()
+	}
+}
@@ -0,0 +1,81 @@
+package subscription
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+)
+
+// Selector picks the metrics of a subscription with the same semantics as
+// the series filter of stored metrics: empty Source or Name match every
+// metric, a missing label matches like an empty one and regular expressions
+// are anchored at both ends.
+type Selector struct {
+	source   string
+	name     string
+	matchers []matcher
+}
+
+type matcher struct {
+	models.LabelMatcher
+	re *regexp.Regexp
+}
+
+func NewSelector(source, name string, matchers []models.LabelMatcher) (Selector, error) {
+	s := Selector{source: source, name: name}
+
+	for _, m := range matchers {
+		compiled := matcher{LabelMatcher: m}
+
+		switch m.Type {
+		case models.MatchEqual, models.MatchNotEqual:
+		case models.MatchRegex, models.MatchNotRegex:
+			re, err := regexp.Compile("^(?:" + m.Value + ")$")
+			if err != nil {
+				return Selector{}, fmt.Errorf("invalid regular expression for label %s: %w", m.Name, err)
+			}
+			compiled.re = re
+		default:
+			return Selector{}, fmt.Errorf("unknown match type %d for label %s", m.Type, m.Name)
+		}
+
+		s.matchers = append(s.matchers, compiled)
+	}
+
+	return s, nil
+}
+
+func (s Selector) Matches(m models.Metric) bool {
+	if s.source != "" && m.Source != s.source {
+		return false
+	}
+	if s.name != "" && m.Name != s.name {
+		return false
+	}
+
+	for _, matcher := range s.matchers {
+		var value string
+		if v, ok := m.Labels[matcher.Name]; ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+
+		var matched bool
+		switch matcher.Type {
+		case models.MatchEqual:
+			matched = value == matcher.Value
+		case models.MatchNotEqual:
+			matched = value != matcher.Value
+		case models.MatchRegex:
+			matched = matcher.re.MatchString(value)
+		case models.MatchNotRegex:
+			matched = !matcher.re.MatchString(value)
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
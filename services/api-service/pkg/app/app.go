@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/database"
+	grpcInternal "github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/grpc"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/internal/subscription"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+// Config is aliased so commands outside this module can load and adjust the
+// api configuration.
+type Config = config.Config
+
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// NewConsumer creates the consumer that feeds subscriptions, or returns nil
+// if they are disabled.
+func NewConsumer(cfg config.SubscriptionsConfig) consumer.MessageConsumer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return kafka.NewKafkaConsumer(cfg.Kafka.Brokers, cfg.Kafka.Topic, subscriptionGroup(cfg.Kafka.GroupPrefix))
+}
+
+// subscriptionGroup names the consumer group of this instance. Instances
+// sharing a group would split the partitions between them.
+func subscriptionGroup(prefix string) string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = fmt.Sprintf("pid-%d", os.Getpid())
+	}
+
+	return prefix + "-" + host
+}
+
+// App serves stored metrics over gRPC. The standalone service and the
+// all-in-one command both run it.
+type App struct {
+	grpcPort   string
+	db         *database.Storage
+	consumer   consumer.MessageConsumer
+	hub        *subscription.Hub
+	grpcServer *grpc.Server
+	log        logger.Logger
+}
+
+// New takes ownership of msgCons, which feeds Subscribe. msgCons may be nil
+// to serve stored metrics only.
+func New(cfg *Config, msgCons consumer.MessageConsumer, log logger.Logger, reg *prometheus.Registry) (*App, error) {
+	m := metrics.NewMetrics(reg)
+
+	db, err := database.New(cfg.Postgres)
+	if err != nil {
+		err = fmt.Errorf("failed to connect to database: %w", err)
+		if msgCons != nil {
+			err = errors.Join(err, msgCons.Close())
+		}
+		return nil, err
+	}
+	log.Info("database connected successfully")
+
+	reader := database.NewPostgresMetricsReeader(db)
+
+	var hub *subscription.Hub
+	if msgCons != nil {
+		hub = subscription.NewHub(msgCons, log, m, cfg.Subscriptions)
+	}
+
+	grpcServer := grpc.NewServer()
+	proto.RegisterMetricsServiceServer(grpcServer, grpcInternal.NewServer(reader, hub, m))
+
+	return &App{
+		grpcPort:   cfg.GRPC.Port,
+		db:         db,
+		consumer:   msgCons,
+		hub:        hub,
+		grpcServer: grpcServer,
+		log:        log,
+	}, nil
+}
+
+// Run serves gRPC and feeds subscriptions until ctx is cancelled or the
+// server fails, then closes the consumer and the database. Subscriptions are
+// ended first, since a graceful stop waits for open streams.
+func (a *App) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", ":"+a.grpcPort)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to listen: %w", err), a.close())
+	}
+
+	hubCtx, cancelHub := context.WithCancel(ctx)
+	hubDone := make(chan struct{})
+	go func() {
+		defer close(hubDone)
+		if a.hub != nil {
+			a.hub.Run(hubCtx)
+		}
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		a.log.Info("gRPC server listening", "port", a.grpcPort)
+		if err := a.grpcServer.Serve(lis); err != nil {
+			errCh <- fmt.Errorf("failed to serve gRPC: %w", err)
+		}
+	}()
+
+	a.log.Info("starting api-service")
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errCh:
+	}
+
+	cancelHub()
+	<-hubDone
+	a.grpcServer.GracefulStop()
+
+	return errors.Join(runErr, a.close())
+}
+
+func (a *App) close() error {
+	errs := []error{a.db.Close()}
+	if a.consumer != nil {
+		errs = append(errs, a.consumer.Close())
+	}
+
+	return errors.Join(errs...)
+}
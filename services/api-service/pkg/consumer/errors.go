@@ -0,0 +1,34 @@
+package consumer
+
+import "fmt"
+
+type UnsupportedContentTypeError struct {
+	ContentType string
+}
+
+func (e UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported message content type '%s'", e.ContentType)
+}
+
+type UnsupportedSchemaVersionError struct {
+	Version uint32
+}
+
+func (e UnsupportedSchemaVersionError) Error() string {
+	return fmt.Sprintf("unsupported message schema version %d, newest supported is %d", e.Version, SchemaVersion)
+}
+
+// DecodeError reports a message that could not be decoded. The consumer
+// moves past it, so callers skip it and fetch the next one.
+type DecodeError struct {
+	ContentType string
+	Err         error
+}
+
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode message: %v", e.Err)
+}
+
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,15 @@
+package consumer
+
+import (
+	"context"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+)
+
+// MessageConsumer hands out metrics as they are published. Subscriptions
+// only stream live metrics, so there is nothing to acknowledge and nothing
+// is redelivered after a restart.
+type MessageConsumer interface {
+	FetchMetric(ctx context.Context) (models.Metric, error)
+	Close() error
+}
@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaConsumer struct {
+	reader *kafka.Reader
+}
+
+// NewKafkaConsumer reads the topic from its newest messages on. Every
+// api-service instance needs a group of its own: members of one group split
+// the partitions between them, and each would stream only its share.
+// Offsets are never committed, so a restarted instance starts from the
+// newest messages again instead of replaying what it missed. Fetches return
+// as soon as a message arrives, since subscribers watch metrics live.
+func NewKafkaConsumer(brokers []string, topic, groupID string) consumer.MessageConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     groupID,
+		MinBytes:    1,
+		MaxBytes:    10e6,
+		MaxWait:     500 * time.Millisecond,
+		StartOffset: kafka.LastOffset,
+	})
+	return &KafkaConsumer{reader: reader}
+}
+
+func (k *KafkaConsumer) FetchMetric(ctx context.Context) (models.Metric, error) {
+	msg, err := k.reader.FetchMessage(ctx)
+	if err != nil {
+		return models.Metric{}, err
+	}
+
+	return consumer.DecodeMetric(contentType(msg.Headers), msg.Value)
+}
+
+// contentTypeHeader is set by the collector's producer on every message.
+const contentTypeHeader = "content-type"
+
+func contentType(headers []kafka.Header) string {
+	for _, header := range headers {
+		if header.Key == contentTypeHeader {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+func (k *KafkaConsumer) Close() error {
+	return k.reader.Close()
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
+)
+
+// Subscription is the receiving end of an in-process bus, such as a consumer
+// group membership on the collector's memory.Bus.
+type Subscription interface {
+	Receive(ctx context.Context) ([]byte, error)
+	Close() error
+}
+
+type MemoryConsumer struct {
+	sub Subscription
+}
+
+func NewMemoryConsumer(sub Subscription) consumer.MessageConsumer {
+	return &MemoryConsumer{sub: sub}
+}
+
+func (m *MemoryConsumer) FetchMetric(ctx context.Context) (models.Metric, error) {
+	payload, err := m.sub.Receive(ctx)
+	if err != nil {
+		return models.Metric{}, err
+	}
+
+	return consumer.DecodeMetric(consumer.ContentTypeJSON, payload)
+}
+
+func (m *MemoryConsumer) Close() error {
+	return m.sub.Close()
+}
@@ -3,10 +3,30 @@ package models
 import "time"
 
 type Metric struct {
+	// ID is the ID of the sample, or of the series for aggregated metrics.
 	ID          int64
 	Source      string
 	Name        string
 	Value       float64
 	Labels      map[string]any
 	CollectedAt time.Time
+	// Aggregate is set for metrics read at a resolution other than raw;
+	// Value then holds the average and CollectedAt the start of the bucket.
+	Aggregate *Aggregate
 }
+
+type Aggregate struct {
+	Min   float64
+	Max   float64
+	Count int64
+}
+
+// Resolution selects raw samples or samples aggregated into buckets.
+type Resolution int
+
+const (
+	ResolutionRaw Resolution = iota
+	ResolutionMinute
+	ResolutionHour
+	ResolutionDay
+)
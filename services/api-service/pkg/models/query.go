@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// MetricsQuery selects the metrics of one source and name. Zero Start or End
+// leave the range open on that side; End is exclusive.
+type MetricsQuery struct {
+	Source     string
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Matchers   []LabelMatcher
+	Resolution Resolution
+	Order      Order
+	Limit      int
+	// After continues a previous query past the last metric it returned.
+	After *Cursor
+}
+
+// LabelMatcher selects series by one label. As in Prometheus, a missing
+// label matches like an empty one and regular expressions are anchored at
+// both ends.
+type LabelMatcher struct {
+	Name  string
+	Type  MatchType
+	Value string
+}
+
+type MatchType int
+
+const (
+	MatchEqual MatchType = iota
+	MatchNotEqual
+	MatchRegex
+	MatchNotRegex
+)
+
+type Order int
+
+const (
+	OrderNewestFirst Order = iota
+	OrderOldestFirst
+)
+
+// Cursor is the position of a metric in the result order: its time and the
+// ID of the sample, or of the series for aggregated metrics.
+type Cursor struct {
+	Time time.Time
+	ID   int64
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// RangeQuery aggregates the samples of the selected series over the steps
+// of [Start, End), counted from Start.
+type RangeQuery struct {
+	Source      string
+	Name        string
+	Matchers    []LabelMatcher
+	Start       time.Time
+	End         time.Time
+	Step        time.Duration
+	Aggregation Aggregation
+}
+
+// Aggregation is the function applied to the samples of each step.
+type Aggregation int
+
+const (
+	AggregationAvg Aggregation = iota
+	AggregationMin
+	AggregationMax
+	AggregationSum
+	AggregationCount
+	AggregationLast
+	AggregationP50
+	AggregationP95
+	AggregationP99
+)
+
+// RangeSeries holds the aggregated values of one series, oldest first.
+type RangeSeries struct {
+	ID     int64
+	Source string
+	Name   string
+	Labels map[string]any
+	Points []Point
+}
+
+// Point is the value of one step; Time is the start of the step.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Series is an entry of the series catalog: one source, name and set of
+// labels, with the time range it has samples for.
+type Series struct {
+	ID          int64
+	Source      string
+	Name        string
+	Labels      map[string]any
+	Unit        string
+	Description string
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+// SeriesQuery selects series from the catalog. Empty Source or Name match
+// every series; non-zero Start and End keep only series with samples in
+// [Start, End).
+type SeriesQuery struct {
+	Source   string
+	Name     string
+	Matchers []LabelMatcher
+	Start    time.Time
+	End      time.Time
+}
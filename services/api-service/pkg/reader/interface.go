@@ -2,11 +2,26 @@ package reader
 
 import (
 	"context"
+	"errors"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/pkg/models"
 )
 
+// ErrInvalidQuery matches the errors a reader returns for queries it cannot
+// run as asked, as opposed to failures of the storage.
+var ErrInvalidQuery = errors.New("invalid query")
+
 type MetricsReader interface {
-	GetMetrics(ctx context.Context, source, name string, limit int) ([]models.Metric, error)
+	// GetMetrics returns a page of metrics and, if there are more, the
+	// cursor to continue after.
+	GetMetrics(ctx context.Context, query models.MetricsQuery) ([]models.Metric, *models.Cursor, error)
 	GetMetric(ctx context.Context, source, name string) (*models.Metric, error)
+	ListSeries(ctx context.Context, source, name string, limit int) ([]models.Series, error)
+	ListSources(ctx context.Context, query models.SeriesQuery) ([]string, error)
+	ListMetricNames(ctx context.Context, query models.SeriesQuery) ([]string, error)
+	ListLabelKeys(ctx context.Context, query models.SeriesQuery) ([]string, error)
+	ListLabelValues(ctx context.Context, query models.SeriesQuery, label string) ([]string, error)
+	// QueryRange returns the aggregated values of every series the query
+	// selects, ordered by series ID.
+	QueryRange(ctx context.Context, query models.RangeQuery) ([]models.RangeSeries, error)
 }
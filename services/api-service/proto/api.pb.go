@@ -22,18 +22,314 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type GetMetricsRequest struct {
+// Resolution selects raw samples or samples aggregated into buckets of one
+// minute, hour or day.
+type Resolution int32
+
+const (
+	Resolution_RESOLUTION_RAW    Resolution = 0
+	Resolution_RESOLUTION_MINUTE Resolution = 1
+	Resolution_RESOLUTION_HOUR   Resolution = 2
+	Resolution_RESOLUTION_DAY    Resolution = 3
+)
+
+// Enum value maps for Resolution.
+var (
+	Resolution_name = map[int32]string{
+		0: "RESOLUTION_RAW",
+		1: "RESOLUTION_MINUTE",
+		2: "RESOLUTION_HOUR",
+		3: "RESOLUTION_DAY",
+	}
+	Resolution_value = map[string]int32{
+		"RESOLUTION_RAW":    0,
+		"RESOLUTION_MINUTE": 1,
+		"RESOLUTION_HOUR":   2,
+		"RESOLUTION_DAY":    3,
+	}
+)
+
+func (x Resolution) Enum() *Resolution {
+	p := new(Resolution)
+	*p = x
+	return p
+}
+
+func (x Resolution) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Resolution) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_api_proto_enumTypes[0].Descriptor()
+}
+
+func (Resolution) Type() protoreflect.EnumType {
+	return &file_proto_api_proto_enumTypes[0]
+}
+
+func (x Resolution) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Resolution.Descriptor instead.
+func (Resolution) EnumDescriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{0}
+}
+
+type Order int32
+
+const (
+	Order_ORDER_NEWEST_FIRST Order = 0
+	Order_ORDER_OLDEST_FIRST Order = 1
+)
+
+// Enum value maps for Order.
+var (
+	Order_name = map[int32]string{
+		0: "ORDER_NEWEST_FIRST",
+		1: "ORDER_OLDEST_FIRST",
+	}
+	Order_value = map[string]int32{
+		"ORDER_NEWEST_FIRST": 0,
+		"ORDER_OLDEST_FIRST": 1,
+	}
+)
+
+func (x Order) Enum() *Order {
+	p := new(Order)
+	*p = x
+	return p
+}
+
+func (x Order) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Order) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_api_proto_enumTypes[1].Descriptor()
+}
+
+func (Order) Type() protoreflect.EnumType {
+	return &file_proto_api_proto_enumTypes[1]
+}
+
+func (x Order) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Order.Descriptor instead.
+func (Order) EnumDescriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{1}
+}
+
+// Aggregation is the function applied to the samples of each step of a
+// range query. The percentiles are interpolated between samples.
+type Aggregation int32
+
+const (
+	Aggregation_AGGREGATION_AVG   Aggregation = 0
+	Aggregation_AGGREGATION_MIN   Aggregation = 1
+	Aggregation_AGGREGATION_MAX   Aggregation = 2
+	Aggregation_AGGREGATION_SUM   Aggregation = 3
+	Aggregation_AGGREGATION_COUNT Aggregation = 4
+	Aggregation_AGGREGATION_LAST  Aggregation = 5
+	Aggregation_AGGREGATION_P50   Aggregation = 6
+	Aggregation_AGGREGATION_P95   Aggregation = 7
+	Aggregation_AGGREGATION_P99   Aggregation = 8
+)
+
+// Enum value maps for Aggregation.
+var (
+	Aggregation_name = map[int32]string{
+		0: "AGGREGATION_AVG",
+		1: "AGGREGATION_MIN",
+		2: "AGGREGATION_MAX",
+		3: "AGGREGATION_SUM",
+		4: "AGGREGATION_COUNT",
+		5: "AGGREGATION_LAST",
+		6: "AGGREGATION_P50",
+		7: "AGGREGATION_P95",
+		8: "AGGREGATION_P99",
+	}
+	Aggregation_value = map[string]int32{
+		"AGGREGATION_AVG":   0,
+		"AGGREGATION_MIN":   1,
+		"AGGREGATION_MAX":   2,
+		"AGGREGATION_SUM":   3,
+		"AGGREGATION_COUNT": 4,
+		"AGGREGATION_LAST":  5,
+		"AGGREGATION_P50":   6,
+		"AGGREGATION_P95":   7,
+		"AGGREGATION_P99":   8,
+	}
+)
+
+func (x Aggregation) Enum() *Aggregation {
+	p := new(Aggregation)
+	*p = x
+	return p
+}
+
+func (x Aggregation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Aggregation) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_api_proto_enumTypes[2].Descriptor()
+}
+
+func (Aggregation) Type() protoreflect.EnumType {
+	return &file_proto_api_proto_enumTypes[2]
+}
+
+func (x Aggregation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Aggregation.Descriptor instead.
+func (Aggregation) EnumDescriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{2}
+}
+
+type LabelMatcher_Type int32
+
+const (
+	LabelMatcher_EQUAL     LabelMatcher_Type = 0
+	LabelMatcher_NOT_EQUAL LabelMatcher_Type = 1
+	LabelMatcher_REGEX     LabelMatcher_Type = 2
+	LabelMatcher_NOT_REGEX LabelMatcher_Type = 3
+)
+
+// Enum value maps for LabelMatcher_Type.
+var (
+	LabelMatcher_Type_name = map[int32]string{
+		0: "EQUAL",
+		1: "NOT_EQUAL",
+		2: "REGEX",
+		3: "NOT_REGEX",
+	}
+	LabelMatcher_Type_value = map[string]int32{
+		"EQUAL":     0,
+		"NOT_EQUAL": 1,
+		"REGEX":     2,
+		"NOT_REGEX": 3,
+	}
+)
+
+func (x LabelMatcher_Type) Enum() *LabelMatcher_Type {
+	p := new(LabelMatcher_Type)
+	*p = x
+	return p
+}
+
+func (x LabelMatcher_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LabelMatcher_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_api_proto_enumTypes[3].Descriptor()
+}
+
+func (LabelMatcher_Type) Type() protoreflect.EnumType {
+	return &file_proto_api_proto_enumTypes[3]
+}
+
+func (x LabelMatcher_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LabelMatcher_Type.Descriptor instead.
+func (LabelMatcher_Type) EnumDescriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// LabelMatcher selects series by one label. As in Prometheus, a missing
+// label matches like an empty one and regular expressions are anchored at
+// both ends.
+type LabelMatcher struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Limit         int64                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          LabelMatcher_Type      `protobuf:"varint,2,opt,name=type,proto3,enum=api.LabelMatcher_Type" json:"type,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LabelMatcher) Reset() {
+	*x = LabelMatcher{}
+	mi := &file_proto_api_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelMatcher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelMatcher) ProtoMessage() {}
+
+func (x *LabelMatcher) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelMatcher.ProtoReflect.Descriptor instead.
+func (*LabelMatcher) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LabelMatcher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LabelMatcher) GetType() LabelMatcher_Type {
+	if x != nil {
+		return x.Type
+	}
+	return LabelMatcher_EQUAL
+}
+
+func (x *LabelMatcher) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type GetMetricsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Source string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// limit is the page size, 100 if unset and at most 10000.
+	Limit      int64      `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Resolution Resolution `protobuf:"varint,4,opt,name=resolution,proto3,enum=api.Resolution" json:"resolution,omitempty"`
+	// start and end are RFC 3339 timestamps bounding collected_at, or the
+	// bucket start for aggregated metrics. end is exclusive; either may be
+	// empty to leave the range open.
+	Start    string          `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`
+	End      string          `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
+	Matchers []*LabelMatcher `protobuf:"bytes,7,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	Order    Order           `protobuf:"varint,8,opt,name=order,proto3,enum=api.Order" json:"order,omitempty"`
+	// page_token is the next_page_token of the previous page, sent with
+	// otherwise unchanged parameters.
+	PageToken     string `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetMetricsRequest) Reset() {
 	*x = GetMetricsRequest{}
-	mi := &file_proto_api_proto_msgTypes[0]
+	mi := &file_proto_api_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -45,7 +341,7 @@ func (x *GetMetricsRequest) String() string {
 func (*GetMetricsRequest) ProtoMessage() {}
 
 func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_api_proto_msgTypes[0]
+	mi := &file_proto_api_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,7 +354,7 @@ func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
 func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_api_proto_rawDescGZIP(), []int{0}
+	return file_proto_api_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *GetMetricsRequest) GetSource() string {
@@ -82,16 +378,60 @@ func (x *GetMetricsRequest) GetLimit() int64 {
 	return 0
 }
 
+func (x *GetMetricsRequest) GetResolution() Resolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return Resolution_RESOLUTION_RAW
+}
+
+func (x *GetMetricsRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+func (x *GetMetricsRequest) GetOrder() Order {
+	if x != nil {
+		return x.Order
+	}
+	return Order_ORDER_NEWEST_FIRST
+}
+
+func (x *GetMetricsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
 type GetMetricsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Metrics       []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Metrics []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	// next_page_token is empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetMetricsResponse) Reset() {
 	*x = GetMetricsResponse{}
-	mi := &file_proto_api_proto_msgTypes[1]
+	mi := &file_proto_api_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -103,7 +443,7 @@ func (x *GetMetricsResponse) String() string {
 func (*GetMetricsResponse) ProtoMessage() {}
 
 func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_api_proto_msgTypes[1]
+	mi := &file_proto_api_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -116,7 +456,7 @@ func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
 func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_api_proto_rawDescGZIP(), []int{1}
+	return file_proto_api_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetMetricsResponse) GetMetrics() []*Metric {
@@ -126,6 +466,13 @@ func (x *GetMetricsResponse) GetMetrics() []*Metric {
 	return nil
 }
 
+func (x *GetMetricsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type GetMetricRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
@@ -136,7 +483,7 @@ type GetMetricRequest struct {
 
 func (x *GetMetricRequest) Reset() {
 	*x = GetMetricRequest{}
-	mi := &file_proto_api_proto_msgTypes[2]
+	mi := &file_proto_api_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -148,7 +495,7 @@ func (x *GetMetricRequest) String() string {
 func (*GetMetricRequest) ProtoMessage() {}
 
 func (x *GetMetricRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_api_proto_msgTypes[2]
+	mi := &file_proto_api_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -161,7 +508,7 @@ func (x *GetMetricRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMetricRequest.ProtoReflect.Descriptor instead.
 func (*GetMetricRequest) Descriptor() ([]byte, []int) {
-	return file_proto_api_proto_rawDescGZIP(), []int{2}
+	return file_proto_api_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetMetricRequest) GetSource() string {
@@ -187,7 +534,7 @@ type GetMetricResponse struct {
 
 func (x *GetMetricResponse) Reset() {
 	*x = GetMetricResponse{}
-	mi := &file_proto_api_proto_msgTypes[3]
+	mi := &file_proto_api_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -199,7 +546,7 @@ func (x *GetMetricResponse) String() string {
 func (*GetMetricResponse) ProtoMessage() {}
 
 func (x *GetMetricResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_api_proto_msgTypes[3]
+	mi := &file_proto_api_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -212,7 +559,7 @@ func (x *GetMetricResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMetricResponse.ProtoReflect.Descriptor instead.
 func (*GetMetricResponse) Descriptor() ([]byte, []int) {
-	return file_proto_api_proto_rawDescGZIP(), []int{3}
+	return file_proto_api_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetMetricResponse) GetMetric() *Metric {
@@ -223,19 +570,22 @@ func (x *GetMetricResponse) GetMetric() *Metric {
 }
 
 type Metric struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
-	Labels        map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	CollectedAt   string                 `protobuf:"bytes,5,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value       float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	Labels      map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CollectedAt string                 `protobuf:"bytes,5,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	// Set for aggregated metrics only. value then holds the average and
+	// collected_at the start of the bucket.
+	Aggregate     *Aggregate `protobuf:"bytes,6,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Metric) Reset() {
 	*x = Metric{}
-	mi := &file_proto_api_proto_msgTypes[4]
+	mi := &file_proto_api_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -247,7 +597,7 @@ func (x *Metric) String() string {
 func (*Metric) ProtoMessage() {}
 
 func (x *Metric) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_api_proto_msgTypes[4]
+	mi := &file_proto_api_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -260,7 +610,7 @@ func (x *Metric) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Metric.ProtoReflect.Descriptor instead.
 func (*Metric) Descriptor() ([]byte, []int) {
-	return file_proto_api_proto_rawDescGZIP(), []int{4}
+	return file_proto_api_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Metric) GetSource() string {
@@ -298,35 +648,1303 @@ func (x *Metric) GetCollectedAt() string {
 	return ""
 }
 
-var File_proto_api_proto protoreflect.FileDescriptor
+func (x *Metric) GetAggregate() *Aggregate {
+	if x != nil {
+		return x.Aggregate
+	}
+	return nil
+}
 
-const file_proto_api_proto_rawDesc = "" +
-	"\n" +
-	"\x0fproto/api.proto\x12\x03api\"U\n" +
-	"\x11GetMetricsRequest\x12\x16\n" +
-	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05limit\x18\x03 \x01(\x03R\x05limit\";\n" +
-	"\x12GetMetricsResponse\x12%\n" +
-	"\ametrics\x18\x01 \x03(\v2\v.api.MetricR\ametrics\">\n" +
-	"\x10GetMetricRequest\x12\x16\n" +
-	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"8\n" +
-	"\x11GetMetricResponse\x12#\n" +
-	"\x06metric\x18\x01 \x01(\v2\v.api.MetricR\x06metric\"\xd9\x01\n" +
-	"\x06Metric\x12\x16\n" +
-	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
-	"\x05value\x18\x03 \x01(\x01R\x05value\x12/\n" +
-	"\x06labels\x18\x04 \x03(\v2\x17.api.Metric.LabelsEntryR\x06labels\x12!\n" +
-	"\fcollected_at\x18\x05 \x01(\tR\vcollectedAt\x1a9\n" +
-	"\vLabelsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\x8b\x01\n" +
-	"\x0eMetricsService\x12=\n" +
-	"\n" +
-	"GetMetrics\x12\x16.api.GetMetricsRequest\x1a\x17.api.GetMetricsResponse\x12:\n" +
-	"\tGetMetric\x12\x15.api.GetMetricRequest\x1a\x16.api.GetMetricResponseB@Z>github.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/protob\x06proto3"
+type Aggregate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           float64                `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64                `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Aggregate) Reset() {
+	*x = Aggregate{}
+	mi := &file_proto_api_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Aggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aggregate) ProtoMessage() {}
+
+func (x *Aggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aggregate.ProtoReflect.Descriptor instead.
+func (*Aggregate) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Aggregate) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Aggregate) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Aggregate) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// ListSeriesRequest filters the series catalog. Empty fields match every
+// series.
+type ListSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Limit         int64                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSeriesRequest) Reset() {
+	*x = ListSeriesRequest{}
+	mi := &file_proto_api_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSeriesRequest) ProtoMessage() {}
+
+func (x *ListSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSeriesRequest.ProtoReflect.Descriptor instead.
+func (*ListSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSeriesRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ListSeriesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListSeriesRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*Series              `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSeriesResponse) Reset() {
+	*x = ListSeriesResponse{}
+	mi := &file_proto_api_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSeriesResponse) ProtoMessage() {}
+
+func (x *ListSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSeriesResponse.ProtoReflect.Descriptor instead.
+func (*ListSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSeriesResponse) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type Series struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Unit          string                 `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	FirstSeen     string                 `protobuf:"bytes,7,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	LastSeen      string                 `protobuf:"bytes,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	mi := &file_proto_api_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Series) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Series) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Series) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Series) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Series) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Series) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Series) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *Series) GetLastSeen() string {
+	if x != nil {
+		return x.LastSeen
+	}
+	return ""
+}
+
+type ListSourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Matchers      []*LabelMatcher        `protobuf:"bytes,2,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	Start         string                 `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourcesRequest) Reset() {
+	*x = ListSourcesRequest{}
+	mi := &file_proto_api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourcesRequest) ProtoMessage() {}
+
+func (x *ListSourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourcesRequest.ProtoReflect.Descriptor instead.
+func (*ListSourcesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListSourcesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListSourcesRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+func (x *ListSourcesRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ListSourcesRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type ListSourcesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []string               `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSourcesResponse) Reset() {
+	*x = ListSourcesResponse{}
+	mi := &file_proto_api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSourcesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSourcesResponse) ProtoMessage() {}
+
+func (x *ListSourcesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSourcesResponse.ProtoReflect.Descriptor instead.
+func (*ListSourcesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListSourcesResponse) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type ListMetricNamesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Matchers      []*LabelMatcher        `protobuf:"bytes,2,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	Start         string                 `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMetricNamesRequest) Reset() {
+	*x = ListMetricNamesRequest{}
+	mi := &file_proto_api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMetricNamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMetricNamesRequest) ProtoMessage() {}
+
+func (x *ListMetricNamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMetricNamesRequest.ProtoReflect.Descriptor instead.
+func (*ListMetricNamesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListMetricNamesRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ListMetricNamesRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+func (x *ListMetricNamesRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ListMetricNamesRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type ListMetricNamesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMetricNamesResponse) Reset() {
+	*x = ListMetricNamesResponse{}
+	mi := &file_proto_api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMetricNamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMetricNamesResponse) ProtoMessage() {}
+
+func (x *ListMetricNamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMetricNamesResponse.ProtoReflect.Descriptor instead.
+func (*ListMetricNamesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListMetricNamesResponse) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type ListLabelKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Matchers      []*LabelMatcher        `protobuf:"bytes,3,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	Start         string                 `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelKeysRequest) Reset() {
+	*x = ListLabelKeysRequest{}
+	mi := &file_proto_api_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelKeysRequest) ProtoMessage() {}
+
+func (x *ListLabelKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListLabelKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListLabelKeysRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ListLabelKeysRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListLabelKeysRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+func (x *ListLabelKeysRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ListLabelKeysRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type ListLabelKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelKeysResponse) Reset() {
+	*x = ListLabelKeysResponse{}
+	mi := &file_proto_api_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelKeysResponse) ProtoMessage() {}
+
+func (x *ListLabelKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListLabelKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListLabelKeysResponse) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// ListLabelValuesRequest asks for the values of one label across the
+// selected series.
+type ListLabelValuesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Matchers      []*LabelMatcher        `protobuf:"bytes,4,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	Start         string                 `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelValuesRequest) Reset() {
+	*x = ListLabelValuesRequest{}
+	mi := &file_proto_api_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelValuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelValuesRequest) ProtoMessage() {}
+
+func (x *ListLabelValuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelValuesRequest.ProtoReflect.Descriptor instead.
+func (*ListLabelValuesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListLabelValuesRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ListLabelValuesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListLabelValuesRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ListLabelValuesRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+func (x *ListLabelValuesRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ListLabelValuesRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type ListLabelValuesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelValuesResponse) Reset() {
+	*x = ListLabelValuesResponse{}
+	mi := &file_proto_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelValuesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelValuesResponse) ProtoMessage() {}
+
+func (x *ListLabelValuesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelValuesResponse.ProtoReflect.Descriptor instead.
+func (*ListLabelValuesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListLabelValuesResponse) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type QueryRangeRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Source   string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name     string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Matchers []*LabelMatcher        `protobuf:"bytes,3,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	// start and end are RFC 3339 timestamps and both required; end is
+	// exclusive. Steps are counted from start.
+	Start string `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End   string `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	// step is a duration such as "30s" or "1h". A range may hold at most
+	// 11000 steps.
+	Step          string      `protobuf:"bytes,6,opt,name=step,proto3" json:"step,omitempty"`
+	Aggregation   Aggregation `protobuf:"varint,7,opt,name=aggregation,proto3,enum=api.Aggregation" json:"aggregation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryRangeRequest) Reset() {
+	*x = QueryRangeRequest{}
+	mi := &file_proto_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeRequest) ProtoMessage() {}
+
+func (x *QueryRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeRequest.ProtoReflect.Descriptor instead.
+func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *QueryRangeRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *QueryRangeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QueryRangeRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+func (x *QueryRangeRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *QueryRangeRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *QueryRangeRequest) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *QueryRangeRequest) GetAggregation() Aggregation {
+	if x != nil {
+		return x.Aggregation
+	}
+	return Aggregation_AGGREGATION_AVG
+}
+
+type QueryRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*RangeSeries         `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryRangeResponse) Reset() {
+	*x = QueryRangeResponse{}
+	mi := &file_proto_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeResponse) ProtoMessage() {}
+
+func (x *QueryRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeResponse.ProtoReflect.Descriptor instead.
+func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *QueryRangeResponse) GetSeries() []*RangeSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+// RangeSeries holds the aggregated values of one series. Steps without
+// samples are left out.
+type RangeSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Points        []*Point               `protobuf:"bytes,5,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeSeries) Reset() {
+	*x = RangeSeries{}
+	mi := &file_proto_api_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeSeries) ProtoMessage() {}
+
+func (x *RangeSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeSeries.ProtoReflect.Descriptor instead.
+func (*RangeSeries) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RangeSeries) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RangeSeries) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RangeSeries) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RangeSeries) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *RangeSeries) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type Point struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// time is the start of the step.
+	Time          string  `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Value         float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_proto_api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Point) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *Point) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// SubscribeRequest selects the metrics to stream as they are collected.
+// Empty source or name match every metric; matchers work as in
+// GetMetricsRequest.
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Matchers      []*LabelMatcher        `protobuf:"bytes,3,rep,name=matchers,proto3" json:"matchers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_proto_api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SubscribeRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetMatchers() []*LabelMatcher {
+	if x != nil {
+		return x.Matchers
+	}
+	return nil
+}
+
+type SubscribeResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Metric *Metric                `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	// dropped is the number of matching metrics skipped before this one
+	// because the client did not keep up.
+	Dropped       int64 `protobuf:"varint,2,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeResponse) Reset() {
+	*x = SubscribeResponse{}
+	mi := &file_proto_api_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeResponse) ProtoMessage() {}
+
+func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SubscribeResponse) GetMetric() *Metric {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+func (x *SubscribeResponse) GetDropped() int64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+var File_proto_api_proto protoreflect.FileDescriptor
+
+const file_proto_api_proto_rawDesc = "" +
+	"\n" +
+	"\x0fproto/api.proto\x12\x03api\"\xa0\x01\n" +
+	"\fLabelMatcher\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12*\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x16.api.LabelMatcher.TypeR\x04type\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\":\n" +
+	"\x04Type\x12\t\n" +
+	"\x05EQUAL\x10\x00\x12\r\n" +
+	"\tNOT_EQUAL\x10\x01\x12\t\n" +
+	"\x05REGEX\x10\x02\x12\r\n" +
+	"\tNOT_REGEX\x10\x03\"\x9e\x02\n" +
+	"\x11GetMetricsRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x03R\x05limit\x12/\n" +
+	"\n" +
+	"resolution\x18\x04 \x01(\x0e2\x0f.api.ResolutionR\n" +
+	"resolution\x12\x14\n" +
+	"\x05start\x18\x05 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x06 \x01(\tR\x03end\x12-\n" +
+	"\bmatchers\x18\a \x03(\v2\x11.api.LabelMatcherR\bmatchers\x12 \n" +
+	"\x05order\x18\b \x01(\x0e2\n" +
+	".api.OrderR\x05order\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\t \x01(\tR\tpageToken\"c\n" +
+	"\x12GetMetricsResponse\x12%\n" +
+	"\ametrics\x18\x01 \x03(\v2\v.api.MetricR\ametrics\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\">\n" +
+	"\x10GetMetricRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"8\n" +
+	"\x11GetMetricResponse\x12#\n" +
+	"\x06metric\x18\x01 \x01(\v2\v.api.MetricR\x06metric\"\x87\x02\n" +
+	"\x06Metric\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12/\n" +
+	"\x06labels\x18\x04 \x03(\v2\x17.api.Metric.LabelsEntryR\x06labels\x12!\n" +
+	"\fcollected_at\x18\x05 \x01(\tR\vcollectedAt\x12,\n" +
+	"\taggregate\x18\x06 \x01(\v2\x0e.api.AggregateR\taggregate\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\tAggregate\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x01R\x03max\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"U\n" +
+	"\x11ListSeriesRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x03R\x05limit\"9\n" +
+	"\x12ListSeriesResponse\x12#\n" +
+	"\x06series\x18\x01 \x03(\v2\v.api.SeriesR\x06series\"\xa2\x02\n" +
+	"\x06Series\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12/\n" +
+	"\x06labels\x18\x04 \x03(\v2\x17.api.Series.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04unit\x18\x05 \x01(\tR\x04unit\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\a \x01(\tR\tfirstSeen\x12\x1b\n" +
+	"\tlast_seen\x18\b \x01(\tR\blastSeen\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x7f\n" +
+	"\x12ListSourcesRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\bmatchers\x18\x02 \x03(\v2\x11.api.LabelMatcherR\bmatchers\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\tR\x03end\"/\n" +
+	"\x13ListSourcesResponse\x12\x18\n" +
+	"\asources\x18\x01 \x03(\tR\asources\"\x87\x01\n" +
+	"\x16ListMetricNamesRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12-\n" +
+	"\bmatchers\x18\x02 \x03(\v2\x11.api.LabelMatcherR\bmatchers\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\tR\x03end\"/\n" +
+	"\x17ListMetricNamesResponse\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"\x99\x01\n" +
+	"\x14ListLabelKeysRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\bmatchers\x18\x03 \x03(\v2\x11.api.LabelMatcherR\bmatchers\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\tR\x03end\"+\n" +
+	"\x15ListLabelKeysResponse\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"\xb1\x01\n" +
+	"\x16ListLabelValuesRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12-\n" +
+	"\bmatchers\x18\x04 \x03(\v2\x11.api.LabelMatcherR\bmatchers\x12\x14\n" +
+	"\x05start\x18\x05 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x06 \x01(\tR\x03end\"1\n" +
+	"\x17ListLabelValuesResponse\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"\xde\x01\n" +
+	"\x11QueryRangeRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\bmatchers\x18\x03 \x03(\v2\x11.api.LabelMatcherR\bmatchers\x12\x14\n" +
+	"\x05start\x18\x04 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x05 \x01(\tR\x03end\x12\x12\n" +
+	"\x04step\x18\x06 \x01(\tR\x04step\x122\n" +
+	"\vaggregation\x18\a \x01(\x0e2\x10.api.AggregationR\vaggregation\">\n" +
+	"\x12QueryRangeResponse\x12(\n" +
+	"\x06series\x18\x01 \x03(\v2\x10.api.RangeSeriesR\x06series\"\xde\x01\n" +
+	"\vRangeSeries\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x124\n" +
+	"\x06labels\x18\x04 \x03(\v2\x1c.api.RangeSeries.LabelsEntryR\x06labels\x12\"\n" +
+	"\x06points\x18\x05 \x03(\v2\n" +
+	".api.PointR\x06points\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"1\n" +
+	"\x05Point\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\tR\x04time\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"m\n" +
+	"\x10SubscribeRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\bmatchers\x18\x03 \x03(\v2\x11.api.LabelMatcherR\bmatchers\"R\n" +
+	"\x11SubscribeResponse\x12#\n" +
+	"\x06metric\x18\x01 \x01(\v2\v.api.MetricR\x06metric\x12\x18\n" +
+	"\adropped\x18\x02 \x01(\x03R\adropped*`\n" +
+	"\n" +
+	"Resolution\x12\x12\n" +
+	"\x0eRESOLUTION_RAW\x10\x00\x12\x15\n" +
+	"\x11RESOLUTION_MINUTE\x10\x01\x12\x13\n" +
+	"\x0fRESOLUTION_HOUR\x10\x02\x12\x12\n" +
+	"\x0eRESOLUTION_DAY\x10\x03*7\n" +
+	"\x05Order\x12\x16\n" +
+	"\x12ORDER_NEWEST_FIRST\x10\x00\x12\x16\n" +
+	"\x12ORDER_OLDEST_FIRST\x10\x01*\xcd\x01\n" +
+	"\vAggregation\x12\x13\n" +
+	"\x0fAGGREGATION_AVG\x10\x00\x12\x13\n" +
+	"\x0fAGGREGATION_MIN\x10\x01\x12\x13\n" +
+	"\x0fAGGREGATION_MAX\x10\x02\x12\x13\n" +
+	"\x0fAGGREGATION_SUM\x10\x03\x12\x15\n" +
+	"\x11AGGREGATION_COUNT\x10\x04\x12\x14\n" +
+	"\x10AGGREGATION_LAST\x10\x05\x12\x13\n" +
+	"\x0fAGGREGATION_P50\x10\x06\x12\x13\n" +
+	"\x0fAGGREGATION_P95\x10\a\x12\x13\n" +
+	"\x0fAGGREGATION_P99\x10\b2\xed\x04\n" +
+	"\x0eMetricsService\x12=\n" +
+	"\n" +
+	"GetMetrics\x12\x16.api.GetMetricsRequest\x1a\x17.api.GetMetricsResponse\x12:\n" +
+	"\tGetMetric\x12\x15.api.GetMetricRequest\x1a\x16.api.GetMetricResponse\x12=\n" +
+	"\n" +
+	"ListSeries\x12\x16.api.ListSeriesRequest\x1a\x17.api.ListSeriesResponse\x12@\n" +
+	"\vListSources\x12\x17.api.ListSourcesRequest\x1a\x18.api.ListSourcesResponse\x12L\n" +
+	"\x0fListMetricNames\x12\x1b.api.ListMetricNamesRequest\x1a\x1c.api.ListMetricNamesResponse\x12F\n" +
+	"\rListLabelKeys\x12\x19.api.ListLabelKeysRequest\x1a\x1a.api.ListLabelKeysResponse\x12L\n" +
+	"\x0fListLabelValues\x12\x1b.api.ListLabelValuesRequest\x1a\x1c.api.ListLabelValuesResponse\x12=\n" +
+	"\n" +
+	"QueryRange\x12\x16.api.QueryRangeRequest\x1a\x17.api.QueryRangeResponse\x12<\n" +
+	"\tSubscribe\x12\x15.api.SubscribeRequest\x1a\x16.api.SubscribeResponse0\x01BIZGgithub.com/MatTwix/Ultimate-Metrics-Platform/services/api-service/protob\x06proto3"
 
 var (
 	file_proto_api_proto_rawDescOnce sync.Once
@@ -340,28 +1958,86 @@ func file_proto_api_proto_rawDescGZIP() []byte {
 	return file_proto_api_proto_rawDescData
 }
 
-var file_proto_api_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_api_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_api_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_proto_api_proto_goTypes = []any{
-	(*GetMetricsRequest)(nil),  // 0: api.GetMetricsRequest
-	(*GetMetricsResponse)(nil), // 1: api.GetMetricsResponse
-	(*GetMetricRequest)(nil),   // 2: api.GetMetricRequest
-	(*GetMetricResponse)(nil),  // 3: api.GetMetricResponse
-	(*Metric)(nil),             // 4: api.Metric
-	nil,                        // 5: api.Metric.LabelsEntry
+	(Resolution)(0),                 // 0: api.Resolution
+	(Order)(0),                      // 1: api.Order
+	(Aggregation)(0),                // 2: api.Aggregation
+	(LabelMatcher_Type)(0),          // 3: api.LabelMatcher.Type
+	(*LabelMatcher)(nil),            // 4: api.LabelMatcher
+	(*GetMetricsRequest)(nil),       // 5: api.GetMetricsRequest
+	(*GetMetricsResponse)(nil),      // 6: api.GetMetricsResponse
+	(*GetMetricRequest)(nil),        // 7: api.GetMetricRequest
+	(*GetMetricResponse)(nil),       // 8: api.GetMetricResponse
+	(*Metric)(nil),                  // 9: api.Metric
+	(*Aggregate)(nil),               // 10: api.Aggregate
+	(*ListSeriesRequest)(nil),       // 11: api.ListSeriesRequest
+	(*ListSeriesResponse)(nil),      // 12: api.ListSeriesResponse
+	(*Series)(nil),                  // 13: api.Series
+	(*ListSourcesRequest)(nil),      // 14: api.ListSourcesRequest
+	(*ListSourcesResponse)(nil),     // 15: api.ListSourcesResponse
+	(*ListMetricNamesRequest)(nil),  // 16: api.ListMetricNamesRequest
+	(*ListMetricNamesResponse)(nil), // 17: api.ListMetricNamesResponse
+	(*ListLabelKeysRequest)(nil),    // 18: api.ListLabelKeysRequest
+	(*ListLabelKeysResponse)(nil),   // 19: api.ListLabelKeysResponse
+	(*ListLabelValuesRequest)(nil),  // 20: api.ListLabelValuesRequest
+	(*ListLabelValuesResponse)(nil), // 21: api.ListLabelValuesResponse
+	(*QueryRangeRequest)(nil),       // 22: api.QueryRangeRequest
+	(*QueryRangeResponse)(nil),      // 23: api.QueryRangeResponse
+	(*RangeSeries)(nil),             // 24: api.RangeSeries
+	(*Point)(nil),                   // 25: api.Point
+	(*SubscribeRequest)(nil),        // 26: api.SubscribeRequest
+	(*SubscribeResponse)(nil),       // 27: api.SubscribeResponse
+	nil,                             // 28: api.Metric.LabelsEntry
+	nil,                             // 29: api.Series.LabelsEntry
+	nil,                             // 30: api.RangeSeries.LabelsEntry
 }
 var file_proto_api_proto_depIdxs = []int32{
-	4, // 0: api.GetMetricsResponse.metrics:type_name -> api.Metric
-	4, // 1: api.GetMetricResponse.metric:type_name -> api.Metric
-	5, // 2: api.Metric.labels:type_name -> api.Metric.LabelsEntry
-	0, // 3: api.MetricsService.GetMetrics:input_type -> api.GetMetricsRequest
-	2, // 4: api.MetricsService.GetMetric:input_type -> api.GetMetricRequest
-	1, // 5: api.MetricsService.GetMetrics:output_type -> api.GetMetricsResponse
-	3, // 6: api.MetricsService.GetMetric:output_type -> api.GetMetricResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3,  // 0: api.LabelMatcher.type:type_name -> api.LabelMatcher.Type
+	0,  // 1: api.GetMetricsRequest.resolution:type_name -> api.Resolution
+	4,  // 2: api.GetMetricsRequest.matchers:type_name -> api.LabelMatcher
+	1,  // 3: api.GetMetricsRequest.order:type_name -> api.Order
+	9,  // 4: api.GetMetricsResponse.metrics:type_name -> api.Metric
+	9,  // 5: api.GetMetricResponse.metric:type_name -> api.Metric
+	28, // 6: api.Metric.labels:type_name -> api.Metric.LabelsEntry
+	10, // 7: api.Metric.aggregate:type_name -> api.Aggregate
+	13, // 8: api.ListSeriesResponse.series:type_name -> api.Series
+	29, // 9: api.Series.labels:type_name -> api.Series.LabelsEntry
+	4,  // 10: api.ListSourcesRequest.matchers:type_name -> api.LabelMatcher
+	4,  // 11: api.ListMetricNamesRequest.matchers:type_name -> api.LabelMatcher
+	4,  // 12: api.ListLabelKeysRequest.matchers:type_name -> api.LabelMatcher
+	4,  // 13: api.ListLabelValuesRequest.matchers:type_name -> api.LabelMatcher
+	4,  // 14: api.QueryRangeRequest.matchers:type_name -> api.LabelMatcher
+	2,  // 15: api.QueryRangeRequest.aggregation:type_name -> api.Aggregation
+	24, // 16: api.QueryRangeResponse.series:type_name -> api.RangeSeries
+	30, // 17: api.RangeSeries.labels:type_name -> api.RangeSeries.LabelsEntry
+	25, // 18: api.RangeSeries.points:type_name -> api.Point
+	4,  // 19: api.SubscribeRequest.matchers:type_name -> api.LabelMatcher
+	9,  // 20: api.SubscribeResponse.metric:type_name -> api.Metric
+	5,  // 21: api.MetricsService.GetMetrics:input_type -> api.GetMetricsRequest
+	7,  // 22: api.MetricsService.GetMetric:input_type -> api.GetMetricRequest
+	11, // 23: api.MetricsService.ListSeries:input_type -> api.ListSeriesRequest
+	14, // 24: api.MetricsService.ListSources:input_type -> api.ListSourcesRequest
+	16, // 25: api.MetricsService.ListMetricNames:input_type -> api.ListMetricNamesRequest
+	18, // 26: api.MetricsService.ListLabelKeys:input_type -> api.ListLabelKeysRequest
+	20, // 27: api.MetricsService.ListLabelValues:input_type -> api.ListLabelValuesRequest
+	22, // 28: api.MetricsService.QueryRange:input_type -> api.QueryRangeRequest
+	26, // 29: api.MetricsService.Subscribe:input_type -> api.SubscribeRequest
+	6,  // 30: api.MetricsService.GetMetrics:output_type -> api.GetMetricsResponse
+	8,  // 31: api.MetricsService.GetMetric:output_type -> api.GetMetricResponse
+	12, // 32: api.MetricsService.ListSeries:output_type -> api.ListSeriesResponse
+	15, // 33: api.MetricsService.ListSources:output_type -> api.ListSourcesResponse
+	17, // 34: api.MetricsService.ListMetricNames:output_type -> api.ListMetricNamesResponse
+	19, // 35: api.MetricsService.ListLabelKeys:output_type -> api.ListLabelKeysResponse
+	21, // 36: api.MetricsService.ListLabelValues:output_type -> api.ListLabelValuesResponse
+	23, // 37: api.MetricsService.QueryRange:output_type -> api.QueryRangeResponse
+	27, // 38: api.MetricsService.Subscribe:output_type -> api.SubscribeResponse
+	30, // [30:39] is the sub-list for method output_type
+	21, // [21:30] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_proto_api_proto_init() }
@@ -374,13 +2050,14 @@ func file_proto_api_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_api_proto_rawDesc), len(file_proto_api_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      4,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_api_proto_goTypes,
 		DependencyIndexes: file_proto_api_proto_depIdxs,
+		EnumInfos:         file_proto_api_proto_enumTypes,
 		MessageInfos:      file_proto_api_proto_msgTypes,
 	}.Build()
 	File_proto_api_proto = out.File
@@ -20,8 +20,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MetricsService_GetMetrics_FullMethodName = "/api.MetricsService/GetMetrics"
-	MetricsService_GetMetric_FullMethodName  = "/api.MetricsService/GetMetric"
+	MetricsService_GetMetrics_FullMethodName      = "/api.MetricsService/GetMetrics"
+	MetricsService_GetMetric_FullMethodName       = "/api.MetricsService/GetMetric"
+	MetricsService_ListSeries_FullMethodName      = "/api.MetricsService/ListSeries"
+	MetricsService_ListSources_FullMethodName     = "/api.MetricsService/ListSources"
+	MetricsService_ListMetricNames_FullMethodName = "/api.MetricsService/ListMetricNames"
+	MetricsService_ListLabelKeys_FullMethodName   = "/api.MetricsService/ListLabelKeys"
+	MetricsService_ListLabelValues_FullMethodName = "/api.MetricsService/ListLabelValues"
+	MetricsService_QueryRange_FullMethodName      = "/api.MetricsService/QueryRange"
+	MetricsService_Subscribe_FullMethodName       = "/api.MetricsService/Subscribe"
 )
 
 // MetricsServiceClient is the client API for MetricsService service.
@@ -30,6 +37,13 @@ const (
 type MetricsServiceClient interface {
 	GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*GetMetricsResponse, error)
 	GetMetric(ctx context.Context, in *GetMetricRequest, opts ...grpc.CallOption) (*GetMetricResponse, error)
+	ListSeries(ctx context.Context, in *ListSeriesRequest, opts ...grpc.CallOption) (*ListSeriesResponse, error)
+	ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error)
+	ListMetricNames(ctx context.Context, in *ListMetricNamesRequest, opts ...grpc.CallOption) (*ListMetricNamesResponse, error)
+	ListLabelKeys(ctx context.Context, in *ListLabelKeysRequest, opts ...grpc.CallOption) (*ListLabelKeysResponse, error)
+	ListLabelValues(ctx context.Context, in *ListLabelValuesRequest, opts ...grpc.CallOption) (*ListLabelValuesResponse, error)
+	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeResponse], error)
 }
 
 type metricsServiceClient struct {
@@ -60,12 +74,98 @@ func (c *metricsServiceClient) GetMetric(ctx context.Context, in *GetMetricReque
 	return out, nil
 }
 
+func (c *metricsServiceClient) ListSeries(ctx context.Context, in *ListSeriesRequest, opts ...grpc.CallOption) (*ListSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSeriesResponse)
+	err := c.cc.Invoke(ctx, MetricsService_ListSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSourcesResponse)
+	err := c.cc.Invoke(ctx, MetricsService_ListSources_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) ListMetricNames(ctx context.Context, in *ListMetricNamesRequest, opts ...grpc.CallOption) (*ListMetricNamesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMetricNamesResponse)
+	err := c.cc.Invoke(ctx, MetricsService_ListMetricNames_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) ListLabelKeys(ctx context.Context, in *ListLabelKeysRequest, opts ...grpc.CallOption) (*ListLabelKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLabelKeysResponse)
+	err := c.cc.Invoke(ctx, MetricsService_ListLabelKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) ListLabelValues(ctx context.Context, in *ListLabelValuesRequest, opts ...grpc.CallOption) (*ListLabelValuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLabelValuesResponse)
+	err := c.cc.Invoke(ctx, MetricsService_ListLabelValues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryRangeResponse)
+	err := c.cc.Invoke(ctx, MetricsService_QueryRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MetricsService_ServiceDesc.Streams[0], MetricsService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, SubscribeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MetricsService_SubscribeClient = grpc.ServerStreamingClient[SubscribeResponse]
+
 // MetricsServiceServer is the server API for MetricsService service.
 // All implementations must embed UnimplementedMetricsServiceServer
 // for forward compatibility.
 type MetricsServiceServer interface {
 	GetMetrics(context.Context, *GetMetricsRequest) (*GetMetricsResponse, error)
 	GetMetric(context.Context, *GetMetricRequest) (*GetMetricResponse, error)
+	ListSeries(context.Context, *ListSeriesRequest) (*ListSeriesResponse, error)
+	ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error)
+	ListMetricNames(context.Context, *ListMetricNamesRequest) (*ListMetricNamesResponse, error)
+	ListLabelKeys(context.Context, *ListLabelKeysRequest) (*ListLabelKeysResponse, error)
+	ListLabelValues(context.Context, *ListLabelValuesRequest) (*ListLabelValuesResponse, error)
+	QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error)
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[SubscribeResponse]) error
 	mustEmbedUnimplementedMetricsServiceServer()
 }
 
@@ -82,6 +182,27 @@ func (UnimplementedMetricsServiceServer) GetMetrics(context.Context, *GetMetrics
 func (UnimplementedMetricsServiceServer) GetMetric(context.Context, *GetMetricRequest) (*GetMetricResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMetric not implemented")
 }
+func (UnimplementedMetricsServiceServer) ListSeries(context.Context, *ListSeriesRequest) (*ListSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSeries not implemented")
+}
+func (UnimplementedMetricsServiceServer) ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSources not implemented")
+}
+func (UnimplementedMetricsServiceServer) ListMetricNames(context.Context, *ListMetricNamesRequest) (*ListMetricNamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetricNames not implemented")
+}
+func (UnimplementedMetricsServiceServer) ListLabelKeys(context.Context, *ListLabelKeysRequest) (*ListLabelKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLabelKeys not implemented")
+}
+func (UnimplementedMetricsServiceServer) ListLabelValues(context.Context, *ListLabelValuesRequest) (*ListLabelValuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLabelValues not implemented")
+}
+func (UnimplementedMetricsServiceServer) QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRange not implemented")
+}
+func (UnimplementedMetricsServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[SubscribeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
 func (UnimplementedMetricsServiceServer) mustEmbedUnimplementedMetricsServiceServer() {}
 func (UnimplementedMetricsServiceServer) testEmbeddedByValue()                        {}
 
@@ -139,6 +260,125 @@ func _MetricsService_GetMetric_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetricsService_ListSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).ListSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_ListSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).ListSeries(ctx, req.(*ListSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_ListSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).ListSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_ListSources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).ListSources(ctx, req.(*ListSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_ListMetricNames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetricNamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).ListMetricNames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_ListMetricNames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).ListMetricNames(ctx, req.(*ListMetricNamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_ListLabelKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).ListLabelKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_ListLabelKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).ListLabelKeys(ctx, req.(*ListLabelKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_ListLabelValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).ListLabelValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_ListLabelValues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).ListLabelValues(ctx, req.(*ListLabelValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_QueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).QueryRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_QueryRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).QueryRange(ctx, req.(*QueryRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetricsServiceServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, SubscribeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MetricsService_SubscribeServer = grpc.ServerStreamingServer[SubscribeResponse]
+
 // MetricsService_ServiceDesc is the grpc.ServiceDesc for MetricsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,7 +394,37 @@ var MetricsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMetric",
 			Handler:    _MetricsService_GetMetric_Handler,
 		},
+		{
+			MethodName: "ListSeries",
+			Handler:    _MetricsService_ListSeries_Handler,
+		},
+		{
+			MethodName: "ListSources",
+			Handler:    _MetricsService_ListSources_Handler,
+		},
+		{
+			MethodName: "ListMetricNames",
+			Handler:    _MetricsService_ListMetricNames_Handler,
+		},
+		{
+			MethodName: "ListLabelKeys",
+			Handler:    _MetricsService_ListLabelKeys_Handler,
+		},
+		{
+			MethodName: "ListLabelValues",
+			Handler:    _MetricsService_ListLabelValues_Handler,
+		},
+		{
+			MethodName: "QueryRange",
+			Handler:    _MetricsService_QueryRange_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _MetricsService_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/api.proto",
 }
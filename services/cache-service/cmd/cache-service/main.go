@@ -3,27 +3,18 @@ package main
 import (
 	"context"
 	"log/slog"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/cache"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/config"
-	grpcInternal "github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/grpc"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/metrics"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/processor"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/consumer"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/app"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/logger"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/proto"
 	"github.com/fsnotify/fsnotify"
-	"github.com/go-redis/redis/v8"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"google.golang.org/grpc"
 )
 
 func main() {
@@ -58,59 +49,32 @@ func main() {
 	})
 
 	cfgMutex.RLock()
-	brokerConfig := cfg.Broker
-	redisConfig := cfg.Redis
-	grpcConfig := cfg.GRPC
-	serverConfig := cfg.Server
+	appConfig := *cfg
 	cfgMutex.RUnlock()
 
 	reg := prometheus.NewRegistry()
-	m := metrics.NewMetrics(reg)
 
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     redisConfig.Addr,
-		Password: redisConfig.Password,
-		DB:       redisConfig.DB,
-	})
-	defer redisClient.Close()
-
-	cacheImpl := cache.NewredisCache(redisClient)
-
-	var msgCons consumer.MessageConsumer
-
-	switch brokerConfig.Type {
-	case "kafka":
-		msgCons = kafka.NewKafkaConsumer(brokerConfig.Kafka.Brokers, brokerConfig.Kafka.Topic, brokerConfig.Kafka.GroupID)
-	default:
-		log.Error("unsupported consumer type", "type", brokerConfig.Type)
+	msgCons, err := app.NewConsumer(appConfig.Broker)
+	if err != nil {
+		log.Error("failed to create consumer", "error", err)
 		os.Exit(1)
 	}
-	defer msgCons.Close()
-
-	proc := processor.NewConsumer(msgCons, cacheImpl, log, m)
 
-	grpcServer := grpc.NewServer()
-	cacheServer := grpcInternal.NewServer(cacheImpl, m)
-	proto.RegisterCacheServiceServer(grpcServer, cacheServer)
+	dlq := app.NewDeadLetterQueue(appConfig.Broker)
+	if dlq == nil {
+		log.Warn("dead-letter queue disabled, messages that cannot be cached will be dropped")
+	}
 
-	lis, err := net.Listen("tcp", ":"+grpcConfig.Port)
+	cacheApp, err := app.New(&appConfig, msgCons, dlq, log, reg)
 	if err != nil {
-		log.Error("failed to listen gRPC server", "error", err)
+		log.Error("failed to start cache", "error", err)
 		os.Exit(1)
 	}
 
-	go func() {
-		log.Info("gRPC server listening", "port", grpcConfig.Port)
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Error("failed to serve gRPC", "error", err)
-			os.Exit(1)
-		}
-	}()
-
 	go func() {
 		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-		log.Info("metrics server listening", "port", serverConfig.Port)
-		if err := http.ListenAndServe(":"+serverConfig.Port, nil); err != nil {
+		log.Info("metrics server listening", "port", appConfig.Server.Port)
+		if err := http.ListenAndServe(":"+appConfig.Server.Port, nil); err != nil {
 			log.Error("failed to start metrics server", "error", err)
 			os.Exit(1)
 		}
@@ -119,14 +83,18 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	log.Info("starting cache-service")
-	proc.Start(ctx)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+		cancel()
+	}()
 
-	cancel()
+	if err := cacheApp.Run(ctx); err != nil {
+		log.Error("cache-service failed", "error", err)
+		os.Exit(1)
+	}
 
 	log.Info("service gracefully stopped")
 }
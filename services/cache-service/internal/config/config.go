@@ -14,6 +14,7 @@ type Config struct {
 	Server ServerConfig `mapstructure:"server"`
 	Redis  RedisConfig  `mapstructure:"redis"`
 	Broker BrokerConfig `mapstructure:"broker"`
+	Retry  RetryConfig  `mapstructure:"retry"`
 	GRPC   GRPCConfig   `mapstructure:"grpc"`
 }
 
@@ -29,15 +30,42 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// RetryConfig is the backoff applied to messages that fail to be handled.
+// After MaxAttempts they are sent to the dead-letter topic.
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Multiplier     float64       `mapstructure:"multiplier"`
+}
+
 type BrokerConfig struct {
-	Type  string      `mapstructure:"type"`
-	Kafka KafkaConfig `mapstructure:"kafka"`
+	Type     string         `mapstructure:"type"`
+	Kafka    KafkaConfig    `mapstructure:"kafka"`
+	NATS     NATSConfig     `mapstructure:"nats"`
+	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
 }
 
 type KafkaConfig struct {
-	Brokers []string `mapstructure:"brokers"`
-	Topic   string   `mapstructure:"topic"`
-	GroupID string   `mapstructure:"group_id"`
+	Brokers         []string `mapstructure:"brokers"`
+	Topic           string   `mapstructure:"topic"`
+	GroupID         string   `mapstructure:"group_id"`
+	DeadLetterTopic string   `mapstructure:"dead_letter_topic"`
+}
+
+type NATSConfig struct {
+	URL     string `mapstructure:"url"`
+	Stream  string `mapstructure:"stream"`
+	Subject string `mapstructure:"subject"`
+	Durable string `mapstructure:"durable"`
+}
+
+type RabbitMQConfig struct {
+	URL        string `mapstructure:"url"`
+	Exchange   string `mapstructure:"exchange"`
+	RoutingKey string `mapstructure:"routing_key"`
+	Queue      string `mapstructure:"queue"`
+	Prefetch   int    `mapstructure:"prefetch"`
 }
 
 type GRPCConfig struct {
@@ -54,6 +54,7 @@ func (s *Server) GetMetric(ctx context.Context, req *proto.GetMetricRequest) (*p
 			Value:       metric.Value,
 			Labels:      labels,
 			CollectedAt: metric.CollectedAt.Format(time.RFC3339),
+			EventId:     metric.EventID,
 		},
 	}, nil
 }
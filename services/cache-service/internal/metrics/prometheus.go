@@ -8,6 +8,7 @@ import (
 type Metrics struct {
 	CacheRequests          prometheus.CounterVec
 	CacheOperationDuration prometheus.HistogramVec
+	DeadLettersTotal       prometheus.CounterVec
 }
 
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -21,5 +22,9 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Help:    "Duration of cache operations",
 			Buckets: prometheus.DefBuckets,
 		}, []string{"operation"}),
+		DeadLettersTotal: *promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_dead_letters_total",
+			Help: "Total number of messages given up on and sent to the dead-letter queue",
+		}, []string{"reason"}),
 	}
 }
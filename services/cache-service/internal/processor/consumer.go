@@ -2,27 +2,45 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/cache"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/config"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/metrics"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/consumer"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/retry"
+)
+
+const (
+	// commitBatch and commitInterval bound how many handled messages wait
+	// for a commit and for how long.
+	commitBatch    = 100
+	commitInterval = time.Second
+
+	shutdownCommitTimeout = 5 * time.Second
 )
 
 type Consumer struct {
 	messageConsumer consumer.MessageConsumer
 	cache           cache.Cache
+	dlq             consumer.DeadLetterQueue
 	log             logger.Logger
 	metrics         *metrics.Metrics
+	retry           retry.Policy
 }
 
-func NewConsumer(messageConsumer consumer.MessageConsumer, cache cache.Cache, log logger.Logger, metrics *metrics.Metrics) *Consumer {
+// NewConsumer creates the cache consumer. dlq may be nil, in which case
+// messages that cannot be cached are logged and dropped.
+func NewConsumer(messageConsumer consumer.MessageConsumer, cache cache.Cache, dlq consumer.DeadLetterQueue, log logger.Logger, metrics *metrics.Metrics, retryCfg config.RetryConfig) *Consumer {
 	return &Consumer{
 		messageConsumer: messageConsumer,
 		cache:           cache,
+		dlq:             dlq,
 		log:             log,
 		metrics:         metrics,
+		retry:           retry.NewPolicy(retryCfg.MaxAttempts, retryCfg.InitialBackoff, retryCfg.MaxBackoff, retryCfg.Multiplier),
 	}
 }
 
@@ -39,42 +57,164 @@ func getTTl(source string) time.Duration {
 	}
 }
 
+// Start caches metrics one at a time. Handled messages are committed in
+// groups, once commitBatch of them are pending or the oldest has waited for
+// commitInterval. A failed cache write is retried under the retry policy and
+// then dead-lettered instead of being skipped.
 func (c *Consumer) Start(ctx context.Context) {
 	c.log.Info("starting cache consumer")
 
+	var uncommitted int
+	var deadline time.Time
+	var fetchFailures int
+
 	for {
-		select {
-		case <-ctx.Done():
-			c.log.Info("consumer stopped")
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if uncommitted > 0 {
+			fetchCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+
+		msg, err := c.messageConsumer.FetchMetric(fetchCtx)
+		cancel()
+
+		var decodeErr consumer.DecodeError
+		switch {
+		case ctx.Err() != nil:
+			c.shutdown(uncommitted)
 			return
-		default:
-			metric, err := c.messageConsumer.ConsumeMetric(ctx)
-			if err != nil {
-				c.metrics.CacheRequests.WithLabelValues("consume", "error").Inc()
+		case errors.Is(err, context.DeadlineExceeded):
+			c.commit(ctx)
+			uncommitted = 0
+		case errors.As(err, &decodeErr):
+			c.metrics.CacheRequests.WithLabelValues("consume", "error").Inc()
+			c.log.Error("failed to decode metric", "error", err)
 
-				c.log.Error("failed to consume metric")
-				time.Sleep(time.Second)
-				continue
+			letter := consumer.DeadLetter{
+				Key:         decodeErr.Key,
+				Payload:     decodeErr.Payload,
+				ContentType: decodeErr.ContentType,
+				Err:         err,
+				Attempts:    1,
+				FailedAt:    time.Now(),
+			}
+			if !c.deadLetter(ctx, "decode", letter) {
+				c.log.Info("consumer stopped")
+				return
 			}
+		case err != nil:
+			fetchFailures++
+			c.metrics.CacheRequests.WithLabelValues("consume", "error").Inc()
+
+			c.log.Error("failed to consume metric", "error", err)
+			c.retry.Wait(ctx, fetchFailures)
+		default:
+			fetchFailures = 0
 
-			if cached, ok := metric.Labels["cached"].(string); ok && cached == "true" {
-				c.log.Debug("skipping cached metric", "source", metric.Source)
-				continue
+			if !c.handle(ctx, msg) {
+				c.log.Info("consumer stopped")
+				return
 			}
 
-			ttl := getTTl(metric.Source)
+			if uncommitted == 0 {
+				deadline = time.Now().Add(commitInterval)
+			}
 
-			start := time.Now()
-			if err := c.cache.SetMetric(ctx, metric, ttl); err != nil {
-				c.metrics.CacheRequests.WithLabelValues("set", "error").Inc()
-				c.log.Error("failed to cache metric", "error", err)
-				continue
+			uncommitted++
+			if uncommitted >= commitBatch {
+				c.commit(ctx)
+				uncommitted = 0
 			}
+		}
+	}
+}
+
+// handle caches the metric, retrying under the retry policy before the
+// message is dead-lettered. It returns false only if ctx was cancelled first.
+func (c *Consumer) handle(ctx context.Context, msg consumer.Message) bool {
+	metric := msg.Metric
 
+	if cached, ok := metric.Labels["cached"].(string); ok && cached == "true" {
+		c.log.Debug("skipping cached metric", "source", metric.Source)
+		return true
+	}
+
+	ttl := getTTl(metric.Source)
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := c.cache.SetMetric(ctx, metric, ttl)
+		if err == nil {
 			c.metrics.CacheRequests.WithLabelValues("set", "success").Inc()
 			c.metrics.CacheOperationDuration.WithLabelValues("set").Observe(time.Since(start).Seconds())
 
 			c.log.Info("successfully cached metric", "source", metric.Source, "name", metric.Name)
+			return true
 		}
+
+		c.metrics.CacheRequests.WithLabelValues("set", "error").Inc()
+		c.log.Error("failed to cache metric", "error", err, "attempt", attempt)
+
+		if attempt >= c.retry.MaxAttempts {
+			return c.deadLetter(ctx, "cache", consumer.DeadLetter{
+				Key:         msg.Key,
+				Payload:     msg.Payload,
+				ContentType: msg.ContentType,
+				Err:         err,
+				Attempts:    attempt,
+				FailedAt:    time.Now(),
+			})
+		}
+
+		if !c.retry.Wait(ctx, attempt) {
+			return false
+		}
+	}
+}
+
+// deadLetter hands letters to the dead-letter queue, retrying under the
+// retry policy. Letters the queue does not accept, or all of them without a
+// queue, are logged and dropped. It reports false if ctx was cancelled first.
+func (c *Consumer) deadLetter(ctx context.Context, reason string, letters ...consumer.DeadLetter) bool {
+	if c.dlq != nil {
+		err := c.retry.Do(ctx, func() error {
+			return c.dlq.Send(ctx, letters...)
+		}, func(err error, attempt int) {
+			c.log.Error("failed to send messages to dead-letter queue", "error", err, "attempt", attempt)
+		})
+		if err == nil {
+			c.log.Warn("messages sent to dead-letter queue", "reason", reason, "count", len(letters))
+			c.metrics.DeadLettersTotal.WithLabelValues(reason).Add(float64(len(letters)))
+
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+
+	for _, letter := range letters {
+		c.log.Error("dropping message that could not be dead-lettered", "error", letter.Err, "attempts", letter.Attempts)
+	}
+	c.metrics.DeadLettersTotal.WithLabelValues(reason).Add(float64(len(letters)))
+
+	return true
+}
+
+func (c *Consumer) commit(ctx context.Context) {
+	if err := c.messageConsumer.Commit(ctx); err != nil {
+		c.metrics.CacheRequests.WithLabelValues("commit", "error").Inc()
+		c.log.Error("failed to commit messages, they will be redelivered", "error", err)
 	}
 }
+
+// shutdown commits the messages handled before the consumer was stopped.
+func (c *Consumer) shutdown(uncommitted int) {
+	if uncommitted > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownCommitTimeout)
+		defer cancel()
+
+		c.commit(ctx)
+	}
+
+	c.log.Info("consumer stopped")
+}
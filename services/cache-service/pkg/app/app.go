@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/cache"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/config"
+	grpcInternal "github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/grpc"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/internal/processor"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/nats"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/rabbitmq"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/proto"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+// Config is aliased so commands outside this module can load and adjust the
+// cache configuration.
+type Config = config.Config
+
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// NewConsumer creates the consumer selected by broker.type.
+func NewConsumer(cfg config.BrokerConfig) (consumer.MessageConsumer, error) {
+	switch cfg.Type {
+	case "kafka":
+		return kafka.NewKafkaConsumer(cfg.Kafka.Brokers, cfg.Kafka.Topic, cfg.Kafka.GroupID), nil
+	case "nats":
+		cons, err := nats.NewNATSConsumer(cfg.NATS.URL, cfg.NATS.Stream, cfg.NATS.Subject, cfg.NATS.Durable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create NATS consumer: %w", err)
+		}
+		return cons, nil
+	case "rabbitmq":
+		cons, err := rabbitmq.NewRabbitMQConsumer(cfg.RabbitMQ.URL, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.RoutingKey, cfg.RabbitMQ.Queue, cfg.RabbitMQ.Prefetch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RabbitMQ consumer: %w", err)
+		}
+		return cons, nil
+	default:
+		return nil, UnsupportedBrokerError{Type: cfg.Type}
+	}
+}
+
+// NewDeadLetterQueue creates the queue for messages that cannot be cached.
+// Dead-lettering needs a Kafka topic, so for other brokers, or without
+// broker.kafka.dead_letter_topic, it returns nil and failed messages are
+// dropped.
+func NewDeadLetterQueue(cfg config.BrokerConfig) consumer.DeadLetterQueue {
+	if cfg.Type != "kafka" || cfg.Kafka.DeadLetterTopic == "" {
+		return nil
+	}
+
+	return kafka.NewDeadLetterWriter(cfg.Kafka.Brokers, cfg.Kafka.DeadLetterTopic, cfg.Kafka.Topic, cfg.Kafka.GroupID)
+}
+
+// App is the cache pipeline, keeping the latest value of every metric in
+// Redis and serving it over gRPC. The standalone service and the all-in-one
+// command both run it.
+type App struct {
+	grpcPort    string
+	redisClient *redis.Client
+	consumer    consumer.MessageConsumer
+	dlq         consumer.DeadLetterQueue
+	proc        *processor.Consumer
+	grpcServer  *grpc.Server
+	log         logger.Logger
+}
+
+// New takes ownership of msgCons and dlq. dlq may be nil.
+func New(cfg *Config, msgCons consumer.MessageConsumer, dlq consumer.DeadLetterQueue, log logger.Logger, reg *prometheus.Registry) (*App, error) {
+	m := metrics.NewMetrics(reg)
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	cacheImpl := cache.NewredisCache(redisClient)
+
+	grpcServer := grpc.NewServer()
+	proto.RegisterCacheServiceServer(grpcServer, grpcInternal.NewServer(cacheImpl, m))
+
+	return &App{
+		grpcPort:    cfg.GRPC.Port,
+		redisClient: redisClient,
+		consumer:    msgCons,
+		dlq:         dlq,
+		proc:        processor.NewConsumer(msgCons, cacheImpl, dlq, log, m, cfg.Retry),
+		grpcServer:  grpcServer,
+		log:         log,
+	}, nil
+}
+
+// Run consumes and serves gRPC until ctx is cancelled or the gRPC server
+// fails, then releases the consumer, the dead-letter queue and the Redis
+// client.
+func (a *App) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", ":"+a.grpcPort)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to listen gRPC server: %w", err), a.close())
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		a.log.Info("gRPC server listening", "port", a.grpcPort)
+		if err := a.grpcServer.Serve(lis); err != nil {
+			errCh <- fmt.Errorf("failed to serve gRPC: %w", err)
+		}
+	}()
+
+	procCtx, cancelProc := context.WithCancel(ctx)
+	procDone := make(chan struct{})
+	go func() {
+		defer close(procDone)
+		a.log.Info("starting cache-service")
+		a.proc.Start(procCtx)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errCh:
+	}
+
+	cancelProc()
+	<-procDone
+	a.grpcServer.GracefulStop()
+
+	return errors.Join(runErr, a.close())
+}
+
+func (a *App) close() error {
+	errs := []error{a.consumer.Close(), a.redisClient.Close()}
+	if a.dlq != nil {
+		errs = append(errs, a.dlq.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+package consumer
+
+import (
+	"encoding/json"
+	"mime"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/models"
+	metricspb "github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// SchemaVersion is the newest envelope version this service understands.
+	SchemaVersion = 1
+
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// DecodeMetric decodes a message payload according to the content type it
+// was published with. Messages without one predate content negotiation and
+// are JSON. Failures are returned as a DecodeError carrying the payload.
+func DecodeMetric(contentType string, payload []byte) (models.Metric, error) {
+	metric, err := decodeMetric(contentType, payload)
+	if err != nil {
+		return models.Metric{}, DecodeError{ContentType: contentType, Payload: payload, Err: err}
+	}
+
+	return metric, nil
+}
+
+// DecodeMessage decodes a payload into a Message that keeps the payload.
+func DecodeMessage(key []byte, contentType string, payload []byte) (Message, error) {
+	metric, err := decodeMetric(contentType, payload)
+	if err != nil {
+		return Message{}, DecodeError{Key: key, ContentType: contentType, Payload: payload, Err: err}
+	}
+
+	return Message{
+		Metric:      metric,
+		Key:         key,
+		Payload:     payload,
+		ContentType: contentType,
+	}, nil
+}
+
+func decodeMetric(contentType string, payload []byte) (models.Metric, error) {
+	mediaType := ContentTypeJSON
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return models.Metric{}, UnsupportedContentTypeError{ContentType: contentType}
+		}
+		mediaType = parsed
+	}
+
+	switch mediaType {
+	case ContentTypeJSON:
+		var metric models.Metric
+		err := json.Unmarshal(payload, &metric)
+		return metric, err
+	case ContentTypeProtobuf:
+		return decodeEnvelope(payload)
+	default:
+		return models.Metric{}, UnsupportedContentTypeError{ContentType: contentType}
+	}
+}
+
+func decodeEnvelope(payload []byte) (models.Metric, error) {
+	var envelope metricspb.MetricEnvelope
+	if err := proto.Unmarshal(payload, &envelope); err != nil {
+		return models.Metric{}, err
+	}
+
+	if envelope.GetSchemaVersion() > SchemaVersion {
+		return models.Metric{}, UnsupportedSchemaVersionError{Version: envelope.GetSchemaVersion()}
+	}
+
+	m := envelope.GetMetric()
+	labels := make(map[string]any, len(m.GetLabels()))
+	for key, value := range m.GetLabels() {
+		switch v := value.GetValue().(type) {
+		case *metricspb.LabelValue_StringValue:
+			labels[key] = v.StringValue
+		case *metricspb.LabelValue_IntValue:
+			labels[key] = v.IntValue
+		case *metricspb.LabelValue_DoubleValue:
+			labels[key] = v.DoubleValue
+		case *metricspb.LabelValue_BoolValue:
+			labels[key] = v.BoolValue
+		}
+	}
+
+	return models.Metric{
+		EventID:     m.GetEventId(),
+		Source:      m.GetSource(),
+		Name:        m.GetName(),
+		Value:       m.GetValue(),
+		Labels:      labels,
+		CollectedAt: m.GetCollectedAt().AsTime(),
+	}, nil
+}
@@ -2,11 +2,44 @@ package consumer
 
 import (
 	"context"
+	"time"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/models"
 )
 
+// Message is a fetched metric together with the payload it was decoded from,
+// kept so a message that cannot be handled is dead-lettered unchanged.
+type Message struct {
+	Metric      models.Metric
+	Key         []byte
+	Payload     []byte
+	ContentType string
+}
+
+// MessageConsumer hands out metrics without acknowledging them. Commit
+// acknowledges every message fetched since the previous commit, including
+// ones that failed to decode, so callers commit once the fetched metrics are
+// handled. Uncommitted messages are redelivered after a restart.
 type MessageConsumer interface {
-	ConsumeMetric(ctx context.Context) (models.Metric, error)
+	FetchMetric(ctx context.Context) (Message, error)
+	Commit(ctx context.Context) error
+	Close() error
+}
+
+// DeadLetter is a message given up on, with the reason and the number of
+// attempts made to handle it.
+type DeadLetter struct {
+	Key         []byte
+	Payload     []byte
+	ContentType string
+	Err         error
+	Attempts    int
+	FailedAt    time.Time
+}
+
+// DeadLetterQueue stores messages that could not be handled so they can be
+// inspected and republished later.
+type DeadLetterQueue interface {
+	Send(ctx context.Context, letters ...DeadLetter) error
 	Close() error
 }
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/consumer"
+)
+
+// Subscription is the receiving end of an in-process bus, such as a consumer
+// group membership on the collector's memory.Bus.
+type Subscription interface {
+	Receive(ctx context.Context) ([]byte, error)
+	Close() error
+}
+
+type MemoryConsumer struct {
+	sub Subscription
+}
+
+func NewMemoryConsumer(sub Subscription) consumer.MessageConsumer {
+	return &MemoryConsumer{sub: sub}
+}
+
+func (m *MemoryConsumer) FetchMetric(ctx context.Context) (consumer.Message, error) {
+	payload, err := m.sub.Receive(ctx)
+	if err != nil {
+		return consumer.Message{}, err
+	}
+
+	return consumer.DecodeMessage(nil, consumer.ContentTypeJSON, payload)
+}
+
+// Commit is a no-op: the bus hands a payload over exactly once and keeps no
+// record of it.
+func (m *MemoryConsumer) Commit(ctx context.Context) error {
+	return nil
+}
+
+func (m *MemoryConsumer) Close() error {
+	return m.sub.Close()
+}
@@ -0,0 +1,120 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/consumer"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	setupTimeout   = 10 * time.Second
+	maxPullPending = 500
+)
+
+type NATSConsumer struct {
+	conn     *nats.Conn
+	consume  jetstream.ConsumeContext
+	messages chan jetstream.Msg
+	done     chan struct{}
+
+	pending []jetstream.Msg
+}
+
+// NewNATSConsumer attaches a durable pull consumer to the stream. Services
+// using the same durable name share the work, like a Kafka consumer group.
+func NewNATSConsumer(url, stream, subject, durable string) (consumer.MessageConsumer, error) {
+	conn, err := nats.Connect(url, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	// The stream may not exist yet if this service starts before the collector.
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     stream,
+		Subjects: []string{subject},
+		Storage:  jetstream.FileStorage,
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", stream, err)
+	}
+
+	cons, err := js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create consumer %s: %w", durable, err)
+	}
+
+	c := &NATSConsumer{
+		conn:     conn,
+		messages: make(chan jetstream.Msg),
+		done:     make(chan struct{}),
+	}
+
+	c.consume, err = cons.Consume(func(msg jetstream.Msg) {
+		select {
+		case c.messages <- msg:
+		case <-c.done:
+		}
+	}, jetstream.PullMaxMessages(maxPullPending))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start consuming: %w", err)
+	}
+
+	return c, nil
+}
+
+func (n *NATSConsumer) FetchMetric(ctx context.Context) (consumer.Message, error) {
+	var msg jetstream.Msg
+	select {
+	case msg = <-n.messages:
+	case <-ctx.Done():
+		return consumer.Message{}, ctx.Err()
+	}
+
+	n.pending = append(n.pending, msg)
+
+	// Collectors send the event ID as the JetStream message ID.
+	key := []byte(msg.Headers().Get(jetstream.MsgIDHeader))
+
+	return consumer.DecodeMessage(key, msg.Headers().Get("Content-Type"), msg.Data())
+}
+
+// Commit acks the pending messages one by one, JetStream has no cumulative
+// ack for pull consumers. Messages whose ack failed are redelivered once
+// their ack wait expires.
+func (n *NATSConsumer) Commit(ctx context.Context) error {
+	defer func() { n.pending = n.pending[:0] }()
+
+	for i, msg := range n.pending {
+		if err := msg.Ack(); err != nil {
+			return fmt.Errorf("failed to ack %d messages: %w", len(n.pending)-i, err)
+		}
+	}
+
+	return nil
+}
+
+func (n *NATSConsumer) Close() error {
+	close(n.done)
+	n.consume.Stop()
+	return n.conn.Drain()
+}
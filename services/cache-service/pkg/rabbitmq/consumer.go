@@ -0,0 +1,157 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/pkg/consumer"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const defaultPrefetch = 500
+
+// RabbitMQConsumer reads from a durable queue bound to the metrics exchange.
+// Each service declares its own queue, so every service receives every metric
+// while instances of one service share their queue. A lost connection is
+// re-established on the next call.
+type RabbitMQConsumer struct {
+	url        string
+	exchange   string
+	routingKey string
+	queue      string
+	prefetch   int
+
+	conn       *amqp.Connection
+	deliveries <-chan amqp.Delivery
+
+	// last is the newest unacknowledged delivery. Acking it with multiple set
+	// acknowledges every delivery before it on the channel.
+	last *amqp.Delivery
+}
+
+func NewRabbitMQConsumer(url, exchange, routingKey, queue string, prefetch int) (consumer.MessageConsumer, error) {
+	if prefetch <= 0 {
+		prefetch = defaultPrefetch
+	}
+
+	r := &RabbitMQConsumer{
+		url:        url,
+		exchange:   exchange,
+		routingKey: routingKey,
+		queue:      queue,
+		prefetch:   prefetch,
+	}
+
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *RabbitMQConsumer) connect() error {
+	conn, err := amqp.Dial(r.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(r.exchange, amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to declare exchange %s: %w", r.exchange, err)
+	}
+
+	if _, err := ch.QueueDeclare(r.queue, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to declare queue %s: %w", r.queue, err)
+	}
+
+	if err := ch.QueueBind(r.queue, r.routingKey, r.exchange, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to bind queue %s: %w", r.queue, err)
+	}
+
+	if err := ch.Qos(r.prefetch, 0, false); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	deliveries, err := ch.Consume(r.queue, "", false, false, false, false, nil)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to consume queue %s: %w", r.queue, err)
+	}
+
+	r.conn = conn
+	r.deliveries = deliveries
+	r.last = nil
+
+	return nil
+}
+
+func (r *RabbitMQConsumer) FetchMetric(ctx context.Context) (consumer.Message, error) {
+	if r.deliveries == nil {
+		if r.conn != nil {
+			r.conn.Close()
+		}
+		if err := r.connect(); err != nil {
+			return consumer.Message{}, err
+		}
+	}
+
+	var delivery amqp.Delivery
+	select {
+	case d, ok := <-r.deliveries:
+		if !ok {
+			r.deliveries = nil
+			return consumer.Message{}, ConnectionClosedError{}
+		}
+		delivery = d
+	case <-ctx.Done():
+		return consumer.Message{}, ctx.Err()
+	}
+
+	r.last = &delivery
+
+	return consumer.DecodeMessage(deliveryKey(delivery), delivery.ContentType, delivery.Body)
+}
+
+// Commit acks every delivery fetched so far. Deliveries from a lost
+// connection cannot be acked; the broker requeues them on its own.
+func (r *RabbitMQConsumer) Commit(ctx context.Context) error {
+	if r.last == nil {
+		return nil
+	}
+
+	last := r.last
+	r.last = nil
+
+	if err := last.Ack(true); err != nil {
+		return fmt.Errorf("failed to ack messages: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RabbitMQConsumer) Close() error {
+	if r.conn == nil {
+		return nil
+	}
+
+	return r.conn.Close()
+}
+
+// deliveryKey is the event ID collectors send as the message ID, or the
+// routing key for messages published without one.
+func deliveryKey(delivery amqp.Delivery) []byte {
+	if delivery.MessageId != "" {
+		return []byte(delivery.MessageId)
+	}
+
+	return []byte(delivery.RoutingKey)
+}
@@ -0,0 +1,7 @@
+package rabbitmq
+
+type ConnectionClosedError struct{}
+
+func (e ConnectionClosedError) Error() string {
+	return "RabbitMQ connection closed, reconnecting on next read"
+}
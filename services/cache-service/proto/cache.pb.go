@@ -125,6 +125,7 @@ type Metric struct {
 	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
 	Labels        map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	CollectedAt   string                 `protobuf:"bytes,5,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	EventId       string                 `protobuf:"bytes,6,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -194,6 +195,13 @@ func (x *Metric) GetCollectedAt() string {
 	return ""
 }
 
+func (x *Metric) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
 var File_proto_cache_proto protoreflect.FileDescriptor
 
 const file_proto_cache_proto_rawDesc = "" +
@@ -203,18 +211,19 @@ const file_proto_cache_proto_rawDesc = "" +
 	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\":\n" +
 	"\x11GetMetricResponse\x12%\n" +
-	"\x06metric\x18\x01 \x01(\v2\r.cache.MetricR\x06metric\"\xdb\x01\n" +
+	"\x06metric\x18\x01 \x01(\v2\r.cache.MetricR\x06metric\"\xf6\x01\n" +
 	"\x06Metric\x12\x16\n" +
 	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05value\x18\x03 \x01(\x01R\x05value\x121\n" +
 	"\x06labels\x18\x04 \x03(\v2\x19.cache.Metric.LabelsEntryR\x06labels\x12!\n" +
-	"\fcollected_at\x18\x05 \x01(\tR\vcollectedAt\x1a9\n" +
+	"\fcollected_at\x18\x05 \x01(\tR\vcollectedAt\x12\x19\n" +
+	"\bevent_id\x18\x06 \x01(\tR\aeventId\x1a9\n" +
 	"\vLabelsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012N\n" +
 	"\fCacheService\x12>\n" +
-	"\tGetMetric\x12\x17.cache.GetMetricRequest\x1a\x18.cache.GetMetricResponseBBZ@github.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/protob\x06proto3"
+	"\tGetMetric\x12\x17.cache.GetMetricRequest\x1a\x18.cache.GetMetricResponseBKZIgithub.com/MatTwix/Ultimate-Metrics-Platform/services/cache-service/protob\x06proto3"
 
 var (
 	file_proto_cache_proto_rawDescOnce sync.Once
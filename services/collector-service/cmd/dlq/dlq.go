@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	collectorKafka "github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/kafka"
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers the consumer services' dead-letter writer adds next to the
+// original content-type header.
+const (
+	headerPrefix        = "dlq-"
+	headerError         = "dlq-error"
+	headerAttempts      = "dlq-attempts"
+	headerOriginalTopic = "dlq-original-topic"
+	headerConsumerGroup = "dlq-consumer-group"
+	headerFailedAt      = "dlq-failed-at"
+)
+
+type deadLetterTopic struct {
+	brokers []string
+	topic   string
+}
+
+// list prints the dead letters oldest first within each partition.
+func (d *deadLetterTopic) list(ctx context.Context, out io.Writer, group string, limit int, withPayload bool) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "POSITION\tFAILED AT\tGROUP\tATTEMPTS\tCONTENT TYPE\tERROR")
+
+	listed := 0
+	err := d.scan(ctx, func(msg kafka.Message) bool {
+		if group != "" && header(msg.Headers, headerConsumerGroup) != group {
+			return true
+		}
+
+		failedAt := header(msg.Headers, headerFailedAt)
+		if t, err := time.Parse(time.RFC3339Nano, failedAt); err == nil {
+			failedAt = t.Local().Format(time.DateTime)
+		}
+
+		fmt.Fprintf(tw, "%d:%d\t%s\t%s\t%s\t%s\t%s\n",
+			msg.Partition,
+			msg.Offset,
+			failedAt,
+			header(msg.Headers, headerConsumerGroup),
+			header(msg.Headers, headerAttempts),
+			header(msg.Headers, collectorKafka.HeaderContentType),
+			header(msg.Headers, headerError),
+		)
+		if withPayload {
+			fmt.Fprintf(tw, "\t%s\n", formatPayload(header(msg.Headers, collectorKafka.HeaderContentType), msg.Value))
+		}
+
+		listed++
+		return limit <= 0 || listed < limit
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+// republish writes the messages at the given partition:offset positions back
+// to the topic they were dead-lettered from, or to the to topic if set. The
+// dead-letter headers are stripped, the key and payload are kept as is.
+func (d *deadLetterTopic) republish(ctx context.Context, out io.Writer, positions []string, to string) error {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(d.brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		WriteTimeout: 10 * time.Second,
+		ReadTimeout:  10 * time.Second,
+	}
+	defer writer.Close()
+
+	for _, position := range positions {
+		partition, offset, err := parsePosition(position)
+		if err != nil {
+			return err
+		}
+
+		msg, err := d.fetch(ctx, partition, offset)
+		if err != nil {
+			return fmt.Errorf("failed to read message %s: %w", position, err)
+		}
+
+		target := to
+		if target == "" {
+			target = header(msg.Headers, headerOriginalTopic)
+		}
+		if target == "" {
+			return fmt.Errorf("message %s has no %s header, use -to", position, headerOriginalTopic)
+		}
+
+		headers := make([]kafka.Header, 0, len(msg.Headers))
+		for _, h := range msg.Headers {
+			if !strings.HasPrefix(h.Key, headerPrefix) {
+				headers = append(headers, h)
+			}
+		}
+
+		if err := writer.WriteMessages(ctx, kafka.Message{
+			Topic:   target,
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: headers,
+		}); err != nil {
+			return fmt.Errorf("failed to republish message %s: %w", position, err)
+		}
+
+		fmt.Fprintf(out, "republished %s to %s\n", position, target)
+	}
+
+	return nil
+}
+
+// scan calls fn for every message in the topic, partition by partition,
+// until fn returns false.
+func (d *deadLetterTopic) scan(ctx context.Context, fn func(kafka.Message) bool) error {
+	conn, err := kafka.DialContext(ctx, "tcp", d.brokers[0])
+	if err != nil {
+		return fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+	partitions, err := conn.ReadPartitions(d.topic)
+	conn.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read partitions of %s: %w", d.topic, err)
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].ID < partitions[j].ID })
+
+	for _, partition := range partitions {
+		first, last, err := d.offsets(ctx, partition.ID)
+		if err != nil {
+			return err
+		}
+		if first >= last {
+			continue
+		}
+
+		reader, err := d.reader(partition.ID, first)
+		if err != nil {
+			return err
+		}
+
+		for offset := first; offset < last; {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				reader.Close()
+				return fmt.Errorf("failed to read partition %d: %w", partition.ID, err)
+			}
+
+			if !fn(msg) {
+				return reader.Close()
+			}
+			offset = msg.Offset + 1
+		}
+
+		reader.Close()
+	}
+
+	return nil
+}
+
+func (d *deadLetterTopic) fetch(ctx context.Context, partition int, offset int64) (kafka.Message, error) {
+	first, last, err := d.offsets(ctx, partition)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	if offset < first || offset >= last {
+		return kafka.Message{}, fmt.Errorf("offset %d is outside of partition %d range [%d, %d)", offset, partition, first, last)
+	}
+
+	reader, err := d.reader(partition, offset)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	defer reader.Close()
+
+	return reader.ReadMessage(ctx)
+}
+
+// offsets returns the first offset of the partition and the offset the next
+// message will be written at.
+func (d *deadLetterTopic) offsets(ctx context.Context, partition int) (int64, int64, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", d.brokers[0], d.topic, partition)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to connect to leader of partition %d: %w", partition, err)
+	}
+	defer conn.Close()
+
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read offsets of partition %d: %w", partition, err)
+	}
+
+	return first, last, nil
+}
+
+func (d *deadLetterTopic) reader(partition int, offset int64) (*kafka.Reader, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   d.brokers,
+		Topic:     d.topic,
+		Partition: partition,
+		MaxBytes:  10e6,
+	})
+
+	if err := reader.SetOffset(offset); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to seek partition %d to offset %d: %w", partition, offset, err)
+	}
+
+	return reader, nil
+}
+
+func parsePosition(position string) (int, int64, error) {
+	partitionStr, offsetStr, ok := strings.Cut(position, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid position %q, expected partition:offset", position)
+	}
+
+	partition, err := strconv.Atoi(partitionStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid partition in %q: %w", position, err)
+	}
+
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid offset in %q: %w", position, err)
+	}
+
+	return partition, offset, nil
+}
+
+func header(headers []kafka.Header, key string) string {
+	for _, h := range headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+// formatPayload prints JSON payloads as they are and anything else, such as
+// protobuf envelopes, base64-encoded.
+func formatPayload(contentType string, payload []byte) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if contentType == "" || (err == nil && mediaType == broker.ContentTypeJSON) {
+		return string(payload)
+	}
+
+	return base64.StdEncoding.EncodeToString(payload)
+}
@@ -0,0 +1,60 @@
+// Command dlq inspects the dead-letter topic the consumer services write
+// failed messages to and republishes selected messages to the topic they
+// came from.
+//
+//	dlq [flags] list
+//	dlq [flags] republish <partition:offset>...
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+func main() {
+	brokers := flag.String("brokers", "localhost:29092", "comma-separated Kafka brokers")
+	topic := flag.String("topic", "metrics-dlq", "dead-letter topic")
+	group := flag.String("group", "", "only show messages dead-lettered by this consumer group")
+	limit := flag.Int("limit", 100, "maximum number of messages to list, 0 for all")
+	payload := flag.Bool("payload", false, "print message payloads when listing")
+	to := flag.String("to", "", "republish to this topic instead of the original one")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] list | republish <partition:offset>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dlq := &deadLetterTopic{
+		brokers: strings.Split(*brokers, ","),
+		topic:   *topic,
+	}
+
+	var err error
+	switch flag.Arg(0) {
+	case "list":
+		err = dlq.list(ctx, os.Stdout, *group, *limit, *payload)
+	case "republish":
+		if flag.NArg() < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = dlq.republish(ctx, os.Stdout, flag.Args()[1:], *to)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dlq:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"fmt"
+	"time"
+)
+
+type UnexpectedStatusError struct {
+	StatusCode int
+}
+
+func (e UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+type RateLimitError struct {
+	Remaining int
+	Reset     time.Time
+}
+
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit reached (%d requests remaining), resets at %s", e.Remaining, e.Reset.Format(time.RFC3339))
+}
@@ -3,55 +3,359 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+const (
+	defaultGithubBaseURL = "https://api.github.com"
+	githubPageSize       = 100
+	etagCacheTTL         = 24 * time.Hour
+)
+
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
 type GithubClient struct {
-	httpClient *http.Client
-	token      string
+	httpClient   *http.Client
+	baseURL      string
+	token        string
+	minRemaining int
+
+	mu        sync.Mutex
+	etags     map[string]etagEntry
+	remaining int
+	reset     time.Time
 }
 
-func NewGithubClient(token string) *GithubClient {
+type etagEntry struct {
+	etag     string
+	body     []byte
+	next     string
+	lastUsed time.Time
+}
+
+func NewGithubClient(baseURL, token string, minRemaining int) *GithubClient {
+	if baseURL == "" {
+		baseURL = defaultGithubBaseURL
+	}
+
 	return &GithubClient{
-		httpClient: &http.Client{},
-		token:      token,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		token:        token,
+		minRemaining: minRemaining,
+		etags:        make(map[string]etagEntry),
+		remaining:    -1,
 	}
 }
 
 type RepoInfo struct {
-	StargazersCount int `json:"stargazers_count"`
+	StargazersCount  int `json:"stargazers_count"`
+	ForksCount       int `json:"forks_count"`
+	SubscribersCount int `json:"subscribers_count"`
+	OpenIssuesCount  int `json:"open_issues_count"`
+}
+
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type WorkflowRun struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type workflowRunsPage struct {
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+// WorkflowRuns holds the fetched runs, newest first, and the number of runs
+// matching the query. Runs is shorter than Total when paging stopped early.
+type WorkflowRuns struct {
+	Runs  []WorkflowRun
+	Total int
 }
 
 func (c *GithubClient) GetRepoInfo(ctx context.Context, repoName string) (*RepoInfo, error) {
-	if !strings.Contains(repoName, "/") {
-		return nil, fmt.Errorf("invalid repo name format, expected 'owner/repo'")
+	if err := validateRepoName(repoName); err != nil {
+		return nil, err
+	}
+
+	var info RepoInfo
+	if err := c.getJSON(ctx, c.baseURL+"/repos/"+repoName, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+func (c *GithubClient) CountOpenPullRequests(ctx context.Context, repoName string) (int, error) {
+	if err := validateRepoName(repoName); err != nil {
+		return 0, err
+	}
+
+	query := url.Values{"state": {"open"}}
+
+	count := 0
+	err := c.paginate(ctx, c.pageURL("/repos/"+repoName+"/pulls", query), func(body []byte) error {
+		var page []json.RawMessage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		count += len(page)
+		return nil
+	})
+
+	return count, err
+}
+
+func (c *GithubClient) CountCommitsSince(ctx context.Context, repoName string, since time.Time) (int, error) {
+	if err := validateRepoName(repoName); err != nil {
+		return 0, err
+	}
+
+	query := url.Values{"since": {since.UTC().Format(time.RFC3339)}}
+
+	count := 0
+	err := c.paginate(ctx, c.pageURL("/repos/"+repoName+"/commits", query), func(body []byte) error {
+		var page []json.RawMessage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		count += len(page)
+		return nil
+	})
+
+	var statusErr UnexpectedStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+		// GitHub answers 409 for repositories without any commits.
+		return 0, nil
 	}
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s", repoName)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	return count, err
+}
+
+func (c *GithubClient) GetLatestRelease(ctx context.Context, repoName string) (*Release, error) {
+	if err := validateRepoName(repoName); err != nil {
+		return nil, err
+	}
+
+	var release Release
+	err := c.getJSON(ctx, c.baseURL+"/repos/"+repoName+"/releases/latest", &release)
+
+	var statusErr UnexpectedStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+func (c *GithubClient) GetCompletedWorkflowRuns(ctx context.Context, repoName string, since time.Time, maxPages int) (WorkflowRuns, error) {
+	if err := validateRepoName(repoName); err != nil {
+		return WorkflowRuns{}, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	query := url.Values{
+		"status":  {"completed"},
+		"created": {">=" + since.UTC().Format("2006-01-02")},
+	}
+
+	var runs WorkflowRuns
+	pages := 0
+	err := c.paginate(ctx, c.pageURL("/repos/"+repoName+"/actions/runs", query), func(body []byte) error {
+		var page workflowRunsPage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		runs.Runs = append(runs.Runs, page.WorkflowRuns...)
+		runs.Total = page.TotalCount
+
+		pages++
+		if maxPages > 0 && pages >= maxPages {
+			return errStopPagination
+		}
+		return nil
+	})
+
+	return runs, err
+}
+
+func (c *GithubClient) RateLimitRemaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remaining
+}
+
+var errStopPagination = errors.New("stop pagination")
+
+func (c *GithubClient) paginate(ctx context.Context, pageURL string, onPage func(body []byte) error) error {
+	for pageURL != "" {
+		body, next, err := c.get(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+
+		if err := onPage(body); err != nil {
+			if errors.Is(err, errStopPagination) {
+				return nil
+			}
+			return fmt.Errorf("failed to decode response body: %w", err)
+		}
+
+		pageURL = next
+	}
+
+	return nil
+}
+
+func (c *GithubClient) pageURL(path string, query url.Values) string {
+	query.Set("per_page", strconv.Itoa(githubPageSize))
+	return c.baseURL + path + "?" + query.Encode()
+}
+
+func (c *GithubClient) getJSON(ctx context.Context, reqURL string, out any) error {
+	body, _, err := c.get(ctx, reqURL)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// get performs a conditional GET. A 304 answer is served from the ETag cache
+// and does not count against the GitHub rate limit.
+func (c *GithubClient) get(ctx context.Context, reqURL string) ([]byte, string, error) {
+	if err := c.checkRateLimit(); err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
+	c.mu.Lock()
+	cached, hasCached := c.etags[reqURL]
+	c.mu.Unlock()
+
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, "", fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	c.updateRateLimit(resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		c.touchETag(reqURL, cached)
+		return cached.body, cached.next, nil
+	case resp.StatusCode != http.StatusOK:
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+			if err := c.checkRateLimit(); err != nil {
+				return nil, "", err
+			}
+		}
+		return nil, "", UnexpectedStatusError{StatusCode: resp.StatusCode}
 	}
 
-	var info RepoInfo
-	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return &info, nil
+	next := ""
+	if match := linkNextRegex.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+		next = match[1]
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.touchETag(reqURL, etagEntry{etag: etag, body: body, next: next})
+	}
+
+	return body, next, nil
+}
+
+func (c *GithubClient) touchETag(reqURL string, entry etagEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	entry.lastUsed = now
+	c.etags[reqURL] = entry
+
+	for k, e := range c.etags {
+		if now.Sub(e.lastUsed) > etagCacheTTL {
+			delete(c.etags, k)
+		}
+	}
+}
+
+func (c *GithubClient) updateRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remaining = remaining
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		c.reset = time.Unix(reset, 0)
+	}
+}
+
+func (c *GithubClient) checkRateLimit() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.remaining < 0 || c.remaining > c.minRemaining {
+		return nil
+	}
+
+	if time.Now().After(c.reset) {
+		c.remaining = -1
+		return nil
+	}
+
+	return RateLimitError{Remaining: c.remaining, Reset: c.reset}
+}
+
+func validateRepoName(repoName string) error {
+	if !strings.Contains(repoName, "/") {
+		return fmt.Errorf("invalid repo name format, expected 'owner/repo'")
+	}
+
+	return nil
 }
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGithubClientFollowsNextLink(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"id":3}]`)
+			return
+		}
+
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls?state=open&page=2>; rel="next", <%s/repos/owner/repo/pulls?state=open&page=2>; rel="last"`, srv.URL, srv.URL))
+		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+	}))
+	defer srv.Close()
+
+	c := NewGithubClient(srv.URL, "", 0)
+
+	count, err := c.CountOpenPullRequests(context.Background(), "owner/repo")
+	if err != nil {
+		t.Fatalf("CountOpenPullRequests: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("count = %d, want 3", count)
+	}
+}
+
+func TestGithubClientReusesCachedResultOnNotModified(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"stargazers_count":42,"forks_count":7}`)
+	}))
+	defer srv.Close()
+
+	c := NewGithubClient(srv.URL, "", 0)
+
+	for i := range 2 {
+		info, err := c.GetRepoInfo(context.Background(), "owner/repo")
+		if err != nil {
+			t.Fatalf("GetRepoInfo #%d: %v", i+1, err)
+		}
+		if info.StargazersCount != 42 || info.ForksCount != 7 {
+			t.Fatalf("GetRepoInfo #%d = %+v, want 42 stars and 7 forks", i+1, *info)
+		}
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("server saw %d requests, want 2", got)
+	}
+}
+
+func TestGithubClientBacksOffUntilRateLimitReset(t *testing.T) {
+	var requests atomic.Int32
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fmt.Fprint(w, `{"stargazers_count":1}`)
+	}))
+	defer srv.Close()
+
+	c := NewGithubClient(srv.URL, "", 0)
+
+	for i := range 2 {
+		_, err := c.GetRepoInfo(context.Background(), "owner/repo")
+
+		var rateErr RateLimitError
+		if !errors.As(err, &rateErr) {
+			t.Fatalf("GetRepoInfo #%d error = %v, want RateLimitError", i+1, err)
+		}
+		if !rateErr.Reset.Equal(reset) {
+			t.Fatalf("GetRepoInfo #%d reset = %s, want %s", i+1, rateErr.Reset, reset)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("server saw %d requests while rate limited, want 1", got)
+	}
+
+	c.mu.Lock()
+	c.reset = time.Now().Add(-time.Second)
+	c.mu.Unlock()
+
+	if _, err := c.GetRepoInfo(context.Background(), "owner/repo"); err != nil {
+		t.Fatalf("GetRepoInfo after reset: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("server saw %d requests after reset, want 2", got)
+	}
+}
@@ -5,30 +5,71 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const defaultOpenWeatherBaseURL = "https://api.openweathermap.org"
+
 type OpenWeatherClient struct {
 	httpClient *http.Client
+	baseURL    string
 	apiKey     string
 }
 
-func NewOpenWeatherClient(apiKey string) *OpenWeatherClient {
+func NewOpenWeatherClient(baseURL, apiKey string) *OpenWeatherClient {
+	if baseURL == "" {
+		baseURL = defaultOpenWeatherBaseURL
+	}
+
 	return &OpenWeatherClient{
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
 		apiKey:     apiKey,
 	}
 }
 
+type Location struct {
+	City string
+	Lat  *float64
+	Lon  *float64
+}
+
 type WeatherData struct {
+	Name string `json:"name"`
 	Main struct {
-		Temp float64 `json:"temp"`
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Pressure  float64 `json:"pressure"`
+		Humidity  float64 `json:"humidity"`
 	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+	} `json:"wind"`
+	Clouds struct {
+		All float64 `json:"all"`
+	} `json:"clouds"`
 }
 
-func (c *OpenWeatherClient) GetCurrentTemperature(ctx context.Context, city string) (*WeatherData, error) {
-	url := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric", city, c.apiKey)
+func (c *OpenWeatherClient) GetCurrentWeather(ctx context.Context, location Location) (*WeatherData, error) {
+	query := url.Values{
+		"appid": {c.apiKey},
+		"units": {"metric"},
+	}
+
+	switch {
+	case location.Lat != nil && location.Lon != nil:
+		query.Set("lat", strconv.FormatFloat(*location.Lat, 'f', -1, 64))
+		query.Set("lon", strconv.FormatFloat(*location.Lon, 'f', -1, 64))
+	case location.City != "":
+		query.Set("q", location.City)
+	default:
+		return nil, fmt.Errorf("location must have either a city or lat/lon coordinates")
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/data/2.5/weather?"+query.Encode(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -40,7 +81,7 @@ func (c *OpenWeatherClient) GetCurrentTemperature(ctx context.Context, city stri
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, UnexpectedStatusError{StatusCode: resp.StatusCode}
 	}
 
 	var data WeatherData
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newWeatherServer(t *testing.T, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/2.5/weather" {
+			http.NotFound(w, r)
+			return
+		}
+		check(r)
+		fmt.Fprint(w, `{"name":"Test","main":{"temp":21.5,"humidity":60}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestOpenWeatherClientEscapesCity(t *testing.T) {
+	var rawQuery string
+	srv := newWeatherServer(t, func(r *http.Request) {
+		rawQuery = r.URL.RawQuery
+	})
+
+	c := NewOpenWeatherClient(srv.URL, "secret")
+
+	data, err := c.GetCurrentWeather(context.Background(), Location{City: "São Paulo"})
+	if err != nil {
+		t.Fatalf("GetCurrentWeather: %v", err)
+	}
+	if data.Main.Temp != 21.5 {
+		t.Fatalf("temp = %v, want 21.5", data.Main.Temp)
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		t.Fatalf("server received an invalid query %q: %v", rawQuery, err)
+	}
+	if got := query.Get("q"); got != "São Paulo" {
+		t.Fatalf("q = %q, want %q (raw query %q)", got, "São Paulo", rawQuery)
+	}
+	if query.Get("appid") != "secret" || query.Get("units") != "metric" {
+		t.Fatalf("unexpected query %q", rawQuery)
+	}
+}
+
+func TestOpenWeatherClientRequestsByCoordinates(t *testing.T) {
+	var query url.Values
+	srv := newWeatherServer(t, func(r *http.Request) {
+		query = r.URL.Query()
+	})
+
+	c := NewOpenWeatherClient(srv.URL, "secret")
+
+	lat, lon := -23.5505, -46.6333
+	if _, err := c.GetCurrentWeather(context.Background(), Location{City: "ignored", Lat: &lat, Lon: &lon}); err != nil {
+		t.Fatalf("GetCurrentWeather: %v", err)
+	}
+
+	if query.Get("lat") != "-23.5505" || query.Get("lon") != "-46.6333" {
+		t.Fatalf("lat/lon = %q/%q, want -23.5505/-46.6333", query.Get("lat"), query.Get("lon"))
+	}
+	if query.Has("q") {
+		t.Fatalf("coordinates request also sent q=%q", query.Get("q"))
+	}
+}
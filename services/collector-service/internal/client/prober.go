@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+const maxProbeBodySize = 1 << 20
+
+type ProbeTarget struct {
+	Name               string
+	URL                string
+	Method             string
+	ExpectedStatus     []int
+	BodyRegex          *regexp.Regexp
+	Headers            map[string]string
+	Timeout            time.Duration
+	FollowRedirects    bool
+	InsecureSkipVerify bool
+}
+
+type ProbeResult struct {
+	Up           bool
+	Reason       string
+	StatusCode   int
+	Duration     time.Duration
+	DNSLookup    time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	TTFB         time.Duration
+	CertExpiry   time.Time
+}
+
+type HTTPProber struct{}
+
+func NewHTTPProber() *HTTPProber {
+	return &HTTPProber{}
+}
+
+func (p *HTTPProber) Probe(ctx context.Context, target ProbeTarget) (*ProbeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, target.Timeout)
+	defer cancel()
+
+	var result ProbeResult
+	timings := &probeTimings{}
+
+	method := target.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, timings.trace(start)), method, target.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range target.Headers {
+		req.Header.Set(k, v)
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: target.InsecureSkipVerify},
+		},
+	}
+	if !target.FollowRedirects {
+		httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		result.Duration = time.Since(start)
+		timings.apply(&result)
+		result.Reason = err.Error()
+		return &result, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodySize))
+	result.Duration = time.Since(start)
+	result.StatusCode = resp.StatusCode
+	timings.apply(&result)
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.CertExpiry = resp.TLS.PeerCertificates[0].NotAfter
+	}
+
+	if err != nil {
+		result.Reason = err.Error()
+		return &result, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if !statusExpected(resp.StatusCode, target.ExpectedStatus) {
+		result.Reason = fmt.Sprintf("unexpected status code: %d", resp.StatusCode)
+		return &result, nil
+	}
+
+	if target.BodyRegex != nil && !target.BodyRegex.Match(body) {
+		result.Reason = fmt.Sprintf("response body does not match %q", target.BodyRegex.String())
+		return &result, nil
+	}
+
+	result.Up = true
+
+	return &result, nil
+}
+
+// probeTimings records the first occurrence of each connection phase. The
+// callbacks may fire concurrently when the dialer races IPv4 and IPv6.
+type probeTimings struct {
+	mu                               sync.Mutex
+	dnsStart, connectStart, tlsStart time.Time
+	dns, connect, tlsHandshake, ttfb time.Duration
+}
+
+func (t *probeTimings) trace(start time.Time) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.dnsStart.IsZero() {
+				t.dnsStart = time.Now()
+			}
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.dns == 0 && !t.dnsStart.IsZero() {
+				t.dns = time.Since(t.dnsStart)
+			}
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil && t.connect == 0 && !t.connectStart.IsZero() {
+				t.connect = time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.tlsStart.IsZero() {
+				t.tlsStart = time.Now()
+			}
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.tlsHandshake == 0 && !t.tlsStart.IsZero() {
+				t.tlsHandshake = time.Since(t.tlsStart)
+			}
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.ttfb == 0 {
+				t.ttfb = time.Since(start)
+			}
+		},
+	}
+}
+
+func (t *probeTimings) apply(result *ProbeResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result.DNSLookup = t.dns
+	result.Connect = t.connect
+	result.TLSHandshake = t.tlsHandshake
+	result.TTFB = t.ttfb
+}
+
+func statusExpected(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 300
+	}
+
+	return slices.Contains(expected, code)
+}
@@ -12,11 +12,13 @@ import (
 type Config struct {
 	Env         string            `mapstructure:"env"`
 	Server      ServerConfig      `mapstructure:"server"`
+	Ingest      IngestConfig      `mapstructure:"ingest"`
 	Worker      WorkerConfig      `mapstructure:"worker"`
 	Github      GithubConfig      `mapstructure:"github"`
 	OpenWeather OpenWeatherConfig `mapstructure:"open_weather"`
 	Broker      BrokerConfig      `mapstructure:"broker"`
 	Urls        UrlsConfig        `mapstructure:"urls"`
+	Sources     []SourceConfig    `mapstructure:"sources"`
 }
 
 type ServerConfig struct {
@@ -25,28 +27,118 @@ type ServerConfig struct {
 	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
 }
 
+type IngestConfig struct {
+	MaxBodyBytes  int64         `mapstructure:"max_body_bytes"`
+	MaxPastSkew   time.Duration `mapstructure:"max_past_skew"`
+	MaxFutureSkew time.Duration `mapstructure:"max_future_skew"`
+
+	RemoteWrite RemoteWriteConfig `mapstructure:"remote_write"`
+	OTLP        OTLPConfig        `mapstructure:"otlp"`
+	Influx      InfluxConfig      `mapstructure:"influx"`
+	Graphite    GraphiteConfig    `mapstructure:"graphite"`
+	StatsD      StatsDConfig      `mapstructure:"statsd"`
+}
+
+type RemoteWriteConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	SourceLabel   string        `mapstructure:"source_label"`
+	DefaultSource string        `mapstructure:"default_source"`
+	MaxBodyBytes  int64         `mapstructure:"max_body_bytes"`
+	MaxPastSkew   time.Duration `mapstructure:"max_past_skew"`
+}
+
+type OTLPConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	GRPCPort        string `mapstructure:"grpc_port"`
+	SourceAttribute string `mapstructure:"source_attribute"`
+	DefaultSource   string `mapstructure:"default_source"`
+	MaxBodyBytes    int64  `mapstructure:"max_body_bytes"`
+}
+
+type InfluxConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	UDPPort      string `mapstructure:"udp_port"`
+	Precision    string `mapstructure:"precision"`
+	MaxBodyBytes int64  `mapstructure:"max_body_bytes"`
+}
+
+type GraphiteConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	TCPPort       string        `mapstructure:"tcp_port"`
+	DefaultSource string        `mapstructure:"default_source"`
+	IdleTimeout   time.Duration `mapstructure:"idle_timeout"`
+}
+
+type StatsDConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	UDPPort         string        `mapstructure:"udp_port"`
+	FlushInterval   time.Duration `mapstructure:"flush_interval"`
+	DefaultSource   string        `mapstructure:"default_source"`
+	MaxTimerSamples int           `mapstructure:"max_timer_samples"`
+}
+
 type WorkerConfig struct {
 	PollInterval time.Duration `mapstructure:"poll_interval"`
 }
 
 type GithubConfig struct {
-	Token      string `mapstructure:"token"`
-	Repository string `mapstructure:"repository"`
+	Token string `mapstructure:"token"`
 }
 
 type OpenWeatherConfig struct {
 	APIKey string `mapstructure:"api_key"`
-	City   string `mapstructure:"city"`
+}
+
+type SourceConfig struct {
+	Type     string         `mapstructure:"type"`
+	Name     string         `mapstructure:"name"`
+	Interval time.Duration  `mapstructure:"interval"`
+	Params   map[string]any `mapstructure:"params"`
 }
 
 type BrokerConfig struct {
-	Type  string      `mapstructure:"type"`
-	Kafka KafkaConfig `mapstructure:"kafka"`
+	Type     string         `mapstructure:"type"`
+	Kafka    KafkaConfig    `mapstructure:"kafka"`
+	NATS     NATSConfig     `mapstructure:"nats"`
+	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
+	Spool    SpoolConfig    `mapstructure:"spool"`
 }
 
 type KafkaConfig struct {
-	Brokers []string `mapstructure:"brokers"`
-	Topic   string   `mapstructure:"topic"`
+	Brokers      []string      `mapstructure:"brokers"`
+	Topic        string        `mapstructure:"topic"`
+	ProducerID   string        `mapstructure:"producer_id"`
+	Encoding     string        `mapstructure:"encoding"`
+	Balancer     string        `mapstructure:"balancer"`
+	Compression  string        `mapstructure:"compression"`
+	RequiredAcks string        `mapstructure:"required_acks"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	BatchBytes   int64         `mapstructure:"batch_bytes"`
+	BatchTimeout time.Duration `mapstructure:"batch_timeout"`
+}
+
+type NATSConfig struct {
+	URL      string `mapstructure:"url"`
+	Stream   string `mapstructure:"stream"`
+	Subject  string `mapstructure:"subject"`
+	Encoding string `mapstructure:"encoding"`
+}
+
+type RabbitMQConfig struct {
+	URL        string `mapstructure:"url"`
+	Exchange   string `mapstructure:"exchange"`
+	RoutingKey string `mapstructure:"routing_key"`
+	Encoding   string `mapstructure:"encoding"`
+}
+
+type SpoolConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Dir           string        `mapstructure:"dir"`
+	SegmentBytes  int64         `mapstructure:"segment_bytes"`
+	MaxBytes      int64         `mapstructure:"max_bytes"`
+	MaxAge        time.Duration `mapstructure:"max_age"`
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+	MaxInFlight   int           `mapstructure:"max_in_flight"`
 }
 
 type UrlsConfig struct {
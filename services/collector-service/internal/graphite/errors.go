@@ -0,0 +1,11 @@
+package graphite
+
+import "fmt"
+
+type ParseError struct {
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("invalid graphite line: %s", e.Reason)
+}
@@ -0,0 +1,81 @@
+package graphite
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+const defaultSource = "graphite"
+
+// Parser reads the Graphite plaintext protocol, including tagged series:
+//
+//	path[;tag=value...] value [timestamp]
+//
+// The first segment of the dotted path becomes the metric source and the rest
+// its name. Paths without a dot are reported under the default source.
+type Parser struct {
+	defaultSource string
+}
+
+func NewParser(defaultSrc string) *Parser {
+	if defaultSrc == "" {
+		defaultSrc = defaultSource
+	}
+
+	return &Parser{defaultSource: defaultSrc}
+}
+
+func (p *Parser) Parse(line []byte) ([]models.Metric, error) {
+	parts := strings.Fields(string(line))
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, ParseError{Reason: "expected 'path value [timestamp]'"}
+	}
+
+	path, tags, _ := strings.Cut(parts[0], ";")
+
+	labels := make(map[string]any)
+	if tags != "" {
+		for tag := range strings.SplitSeq(tags, ";") {
+			key, value, ok := strings.Cut(tag, "=")
+			if !ok || key == "" || value == "" {
+				return nil, ParseError{Reason: "invalid tag '" + tag + "'"}
+			}
+			labels[key] = value
+		}
+	}
+
+	source, name, ok := strings.Cut(path, ".")
+	if !ok {
+		source, name = p.defaultSource, path
+	}
+	if source == "" || name == "" {
+		return nil, ParseError{Reason: "invalid path '" + path + "'"}
+	}
+
+	value, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return nil, ParseError{Reason: "invalid value '" + parts[1] + "'"}
+	}
+
+	var collectedAt time.Time
+	if len(parts) == 3 && parts[2] != "-1" && parts[2] != "N" {
+		ts, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return nil, ParseError{Reason: "invalid timestamp '" + parts[2] + "'"}
+		}
+		sec, frac := math.Modf(ts)
+		collectedAt = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	}
+
+	return []models.Metric{{
+		Source:      source,
+		Name:        name,
+		Value:       value,
+		Labels:      labels,
+		CollectedAt: collectedAt,
+	}}, nil
+}
@@ -0,0 +1,19 @@
+package influx
+
+import "fmt"
+
+type ParseError struct {
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("invalid line protocol: %s", e.Reason)
+}
+
+type UnknownPrecisionError struct {
+	Precision string
+}
+
+func (e UnknownPrecisionError) Error() string {
+	return fmt.Sprintf("unknown precision '%s'", e.Precision)
+}
@@ -0,0 +1,223 @@
+package influx
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+// Parser reads InfluxDB line protocol:
+//
+//	measurement[,tag=value...] field=value[,field=value...] [timestamp]
+//
+// The measurement becomes the metric source, every numeric field a metric
+// named after the field key, and the tags its labels. String fields carry no
+// numeric value and are skipped.
+type Parser struct {
+	precision time.Duration
+}
+
+func NewParser(precision time.Duration) *Parser {
+	if precision <= 0 {
+		precision = time.Nanosecond
+	}
+
+	return &Parser{precision: precision}
+}
+
+// ParsePrecision maps the precision names accepted by the InfluxDB write API
+// onto durations. An empty string means nanoseconds.
+func ParsePrecision(s string) (time.Duration, error) {
+	switch s {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u", "us", "µ", "µs":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	default:
+		return 0, UnknownPrecisionError{Precision: s}
+	}
+}
+
+func (p *Parser) Parse(line []byte) ([]models.Metric, error) {
+	s := lineScanner{line: string(line)}
+
+	measurement := s.token(", ")
+	if measurement == "" {
+		return nil, ParseError{Reason: "missing measurement"}
+	}
+
+	labels := make(map[string]any)
+	for s.peek() == ',' {
+		s.pos++
+
+		key := s.token("=, ")
+		if key == "" || s.peek() != '=' {
+			return nil, ParseError{Reason: "invalid tag"}
+		}
+		s.pos++
+
+		value := s.token(", ")
+		if value == "" {
+			return nil, ParseError{Reason: "missing value for tag '" + key + "'"}
+		}
+		labels[key] = value
+	}
+
+	if !s.skipSpaces() {
+		return nil, ParseError{Reason: "missing fields"}
+	}
+
+	type field struct {
+		key   string
+		value float64
+	}
+	var fields []field
+
+	for {
+		key := s.token("=, ")
+		if key == "" || s.peek() != '=' {
+			return nil, ParseError{Reason: "invalid field"}
+		}
+		s.pos++
+
+		if s.peek() == '"' {
+			if !s.skipQuoted() {
+				return nil, ParseError{Reason: "unterminated string value for field '" + key + "'"}
+			}
+		} else {
+			raw := s.token(", ")
+			value, err := parseFieldValue(raw)
+			if err != nil {
+				return nil, ParseError{Reason: "invalid value for field '" + key + "': " + raw}
+			}
+			fields = append(fields, field{key: key, value: value})
+		}
+
+		if s.peek() != ',' {
+			break
+		}
+		s.pos++
+	}
+
+	var collectedAt time.Time
+	if s.skipSpaces() {
+		raw := s.token(" ")
+		ts, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, ParseError{Reason: "invalid timestamp: " + raw}
+		}
+		collectedAt = time.Unix(0, ts*int64(p.precision))
+	}
+
+	if s.skipSpaces() {
+		return nil, ParseError{Reason: "unexpected trailing data"}
+	}
+
+	if len(fields) == 0 {
+		return nil, ParseError{Reason: "no numeric fields"}
+	}
+
+	metrics := make([]models.Metric, 0, len(fields))
+	for _, f := range fields {
+		metrics = append(metrics, models.Metric{
+			Source:      measurement,
+			Name:        f.key,
+			Value:       f.value,
+			Labels:      labels,
+			CollectedAt: collectedAt,
+		})
+	}
+
+	return metrics, nil
+}
+
+func parseFieldValue(raw string) (float64, error) {
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, nil
+	}
+
+	switch {
+	case strings.HasSuffix(raw, "i"):
+		v, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		return float64(v), err
+	case strings.HasSuffix(raw, "u"):
+		v, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		return float64(v), err
+	default:
+		return strconv.ParseFloat(raw, 64)
+	}
+}
+
+type lineScanner struct {
+	line string
+	pos  int
+}
+
+func (s *lineScanner) peek() byte {
+	if s.pos >= len(s.line) {
+		return 0
+	}
+	return s.line[s.pos]
+}
+
+// token reads up to the first unescaped byte from stops. A backslash escapes
+// a following comma, equals sign, space or backslash; any other backslash is
+// kept as is.
+func (s *lineScanner) token(stops string) string {
+	var sb strings.Builder
+
+	for s.pos < len(s.line) {
+		c := s.line[s.pos]
+		if c == '\\' && s.pos+1 < len(s.line) {
+			next := s.line[s.pos+1]
+			if strings.IndexByte(`,= \`, next) >= 0 {
+				sb.WriteByte(next)
+				s.pos += 2
+				continue
+			}
+		}
+		if strings.IndexByte(stops, c) >= 0 {
+			break
+		}
+
+		sb.WriteByte(c)
+		s.pos++
+	}
+
+	return sb.String()
+}
+
+func (s *lineScanner) skipQuoted() bool {
+	for s.pos++; s.pos < len(s.line); s.pos++ {
+		switch s.line[s.pos] {
+		case '\\':
+			s.pos++
+		case '"':
+			s.pos++
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipSpaces advances past spaces and reports whether any input remains.
+func (s *lineScanner) skipSpaces() bool {
+	for s.peek() == ' ' {
+		s.pos++
+	}
+	return s.pos < len(s.line)
+}
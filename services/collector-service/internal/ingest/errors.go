@@ -0,0 +1,25 @@
+package ingest
+
+import "fmt"
+
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Reason)
+}
+
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,133 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+// LineParser turns a single line of a text protocol into metrics.
+type LineParser func(line []byte) ([]models.Metric, error)
+
+type LineResult struct {
+	Accepted int
+	Rejected int
+	Errors   []LineError
+}
+
+// LineIngester parses, validates and publishes text protocol payloads. A line
+// is accepted only if every metric parsed from it passes validation.
+type LineIngester struct {
+	protocol  string
+	validator *Validator
+	broker    broker.MessageBroker
+	log       logger.Logger
+	metrics   *metrics.Metrics
+}
+
+func NewLineIngester(protocol string, validator *Validator, broker broker.MessageBroker, log logger.Logger, m *metrics.Metrics) *LineIngester {
+	return &LineIngester{
+		protocol:  protocol,
+		validator: validator,
+		broker:    broker,
+		log:       log,
+		metrics:   m,
+	}
+}
+
+// Line is a line of a text protocol payload with its number in the payload,
+// counting from 1.
+type Line struct {
+	Number int
+	Text   []byte
+}
+
+// SplitLines splits a payload into lines, dropping blank lines and comments.
+// The lines keep their numbers in the payload, so errors point at the right
+// line.
+func SplitLines(data []byte) []Line {
+	var lines []Line
+
+	n := 0
+	for text := range bytes.SplitSeq(data, []byte("\n")) {
+		n++
+		text = bytes.TrimSpace(text)
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		lines = append(lines, Line{Number: n, Text: text})
+	}
+
+	return lines
+}
+
+// Ingest publishes the valid lines and reports the rest. An error is returned
+// only when the broker failed to accept the batch.
+func (i *LineIngester) Ingest(ctx context.Context, lines []Line, parse LineParser) (LineResult, error) {
+	var res LineResult
+	var batch []models.Metric
+	var batchLines []int
+
+	for n, line := range lines {
+		parsed, err := parse(line.Text)
+		if err == nil {
+			for j := range parsed {
+				if err = i.validator.Validate(&parsed[j]); err != nil {
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			res.Errors = append(res.Errors, LineError{Line: line.Number, Err: err})
+			continue
+		}
+
+		for range parsed {
+			batchLines = append(batchLines, n)
+		}
+		batch = append(batch, parsed...)
+	}
+
+	if len(batch) > 0 {
+		if err := i.broker.SendMetrics(ctx, batch); err != nil {
+			var serErr *broker.SerialisationError
+			if !errors.As(err, &serErr) {
+				return res, err
+			}
+
+			i.log.Warn("some metrics failed to be serialised", "protocol", i.protocol, "error", serErr)
+			failed := make(map[int]bool, len(serErr.FailedIndexes))
+			for j, idx := range serErr.FailedIndexes {
+				line := batchLines[idx]
+				if failed[line] {
+					continue
+				}
+				failed[line] = true
+
+				var lineErr error = serErr
+				if j < len(serErr.Errors) {
+					lineErr = serErr.Errors[j]
+				}
+				res.Errors = append(res.Errors, LineError{Line: lines[line].Number, Err: lineErr})
+			}
+		}
+	}
+
+	res.Rejected = len(res.Errors)
+	res.Accepted = len(lines) - res.Rejected
+
+	i.metrics.IngestedMetrics.WithLabelValues(i.protocol, "accepted").Add(float64(res.Accepted))
+	i.metrics.IngestedMetrics.WithLabelValues(i.protocol, "rejected").Add(float64(res.Rejected))
+
+	if res.Rejected > 0 {
+		i.log.Debug("lines rejected", "protocol", i.protocol, "count", res.Rejected, "first_error", res.Errors[0])
+	}
+
+	return res, nil
+}
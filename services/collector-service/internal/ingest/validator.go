@@ -0,0 +1,112 @@
+package ingest
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+const (
+	defaultMaxPastSkew   = time.Hour
+	defaultMaxFutureSkew = 5 * time.Minute
+)
+
+var labelKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type Validator struct {
+	maxPastSkew   time.Duration
+	maxFutureSkew time.Duration
+	now           func() time.Time
+}
+
+func NewValidator(cfg config.IngestConfig) *Validator {
+	v := &Validator{
+		maxPastSkew:   cfg.MaxPastSkew,
+		maxFutureSkew: cfg.MaxFutureSkew,
+		now:           time.Now,
+	}
+
+	if v.maxPastSkew <= 0 {
+		v.maxPastSkew = defaultMaxPastSkew
+	}
+	if v.maxFutureSkew <= 0 {
+		v.maxFutureSkew = defaultMaxFutureSkew
+	}
+
+	return v
+}
+
+// Validate checks a single metric and normalises it in place: surrounding
+// whitespace is trimmed and a missing timestamp is set to the current time.
+func (v *Validator) Validate(m *models.Metric) error {
+	m.Source = strings.TrimSpace(m.Source)
+	m.Name = strings.TrimSpace(m.Name)
+
+	if m.Source == "" {
+		return ValidationError{Field: "source", Reason: "is required"}
+	}
+
+	if m.Name == "" {
+		return ValidationError{Field: "name", Reason: "is required"}
+	}
+
+	if math.IsNaN(m.Value) || math.IsInf(m.Value, 0) {
+		return ValidationError{Field: "value", Reason: "must be a finite number"}
+	}
+
+	now := v.now()
+	if m.CollectedAt.IsZero() {
+		m.CollectedAt = now
+	}
+
+	if m.CollectedAt.Before(now.Add(-v.maxPastSkew)) {
+		return ValidationError{Field: "collected_at", Reason: "is older than " + v.maxPastSkew.String()}
+	}
+
+	if m.CollectedAt.After(now.Add(v.maxFutureSkew)) {
+		return ValidationError{Field: "collected_at", Reason: "is more than " + v.maxFutureSkew.String() + " in the future"}
+	}
+
+	for k, val := range m.Labels {
+		if !labelKeyRegex.MatchString(k) {
+			return ValidationError{Field: "labels", Reason: "contain invalid key '" + k + "'"}
+		}
+
+		if _, ok := val.(string); !ok {
+			return ValidationError{Field: "labels", Reason: "contain non-string value for key '" + k + "'"}
+		}
+	}
+
+	return nil
+}
+
+// SanitizeLabelKey maps keys such as "host.name" onto the label key alphabet
+// accepted by Validate by replacing every other character with an underscore.
+func SanitizeLabelKey(key string) string {
+	var sb strings.Builder
+	sb.Grow(len(key) + 1)
+
+	for i, r := range key {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "_"
+	}
+
+	return sb.String()
+}
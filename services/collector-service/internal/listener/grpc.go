@@ -0,0 +1,56 @@
+package listener
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+type GRPCListener struct {
+	name   string
+	addr   string
+	server *grpc.Server
+	log    logger.Logger
+}
+
+func NewGRPC(name, addr string, server *grpc.Server, log logger.Logger) *GRPCListener {
+	return &GRPCListener{
+		name:   name,
+		addr:   addr,
+		server: server,
+		log:    log,
+	}
+}
+
+func (l *GRPCListener) Name() string {
+	return l.name
+}
+
+func (l *GRPCListener) Serve() error {
+	lis, err := net.Listen("tcp", l.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s on %s: %w", l.name, l.addr, err)
+	}
+
+	l.log.Info("gRPC listener is ready to handle requests", "listener", l.name, "addr", l.addr)
+	return l.server.Serve(lis)
+}
+
+func (l *GRPCListener) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		l.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		l.server.Stop()
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+)
+
+// Listener is a network endpoint that runs alongside the HTTP server. Serve
+// blocks until the listener fails or Shutdown is called, in which case it
+// returns nil.
+type Listener interface {
+	Name() string
+	Serve() error
+	Shutdown(ctx context.Context) error
+}
+
+// LineHandler consumes a batch of lines received by a text protocol listener.
+type LineHandler func(ctx context.Context, lines []ingest.Line)
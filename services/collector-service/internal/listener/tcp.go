@@ -0,0 +1,217 @@
+package listener
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+)
+
+const (
+	defaultIdleTimeout = 5 * time.Minute
+	maxLineBytes       = 64 * 1024
+	maxBatchLines      = 1000
+)
+
+// TCPListener reads newline separated lines from long-lived connections.
+// Lines are handed over in batches whenever the client pauses or the batch
+// grows to maxBatchLines.
+type TCPListener struct {
+	name        string
+	addr        string
+	idleTimeout time.Duration
+	handler     LineHandler
+	log         logger.Logger
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewTCP(name, addr string, idleTimeout time.Duration, handler LineHandler, log logger.Logger) *TCPListener {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TCPListener{
+		name:        name,
+		addr:        addr,
+		idleTimeout: idleTimeout,
+		handler:     handler,
+		log:         log,
+		conns:       make(map[net.Conn]struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (l *TCPListener) Name() string {
+	return l.name
+}
+
+func (l *TCPListener) Serve() error {
+	lis, err := net.Listen("tcp", l.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s on %s: %w", l.name, l.addr, err)
+	}
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		lis.Close()
+		return nil
+	}
+	l.listener = lis
+	l.mu.Unlock()
+
+	l.log.Info("TCP listener is ready to handle connections", "listener", l.name, "addr", l.addr)
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			l.log.Warn("failed to accept TCP connection", "listener", l.name, "error", err)
+			continue
+		}
+
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		l.conns[conn] = struct{}{}
+		l.wg.Add(1)
+		l.mu.Unlock()
+
+		go l.handleConn(conn)
+	}
+}
+
+func (l *TCPListener) handleConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+
+		l.mu.Lock()
+		delete(l.conns, conn)
+		l.mu.Unlock()
+
+		l.wg.Done()
+	}()
+
+	reader := bufio.NewReaderSize(conn, maxLineBytes)
+	var batch []ingest.Line
+	// Lines are numbered from the start of the connection.
+	var lineNo int
+
+	flush := func() {
+		if len(batch) > 0 {
+			l.handler(l.ctx, batch)
+			batch = nil
+		}
+	}
+	defer flush()
+
+	for {
+		if !l.extendDeadline(conn) {
+			return
+		}
+
+		line, err := reader.ReadSlice('\n')
+		lineNo++
+		if errors.Is(err, bufio.ErrBufferFull) {
+			l.log.Warn("dropping oversized line", "listener", l.name, "remote", conn.RemoteAddr().String(), "limit", maxLineBytes)
+			if !discardLine(reader) {
+				return
+			}
+			continue
+		}
+
+		// ReadSlice returns a view into the reader's buffer.
+		if line = bytes.TrimSpace(line); len(line) > 0 && line[0] != '#' {
+			batch = append(batch, ingest.Line{Number: lineNo, Text: bytes.Clone(line)})
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				l.log.Debug("TCP connection closed", "listener", l.name, "remote", conn.RemoteAddr().String(), "error", err)
+			}
+			return
+		}
+
+		if reader.Buffered() == 0 || len(batch) >= maxBatchLines {
+			flush()
+		}
+	}
+}
+
+// extendDeadline pushes the idle deadline forward unless the listener is
+// shutting down, in which case Shutdown has already expired it.
+func (l *TCPListener) extendDeadline(conn net.Conn) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+
+	return conn.SetReadDeadline(time.Now().Add(l.idleTimeout)) == nil
+}
+
+// discardLine skips the rest of an oversized line and reports whether the
+// connection is still usable.
+func discardLine(reader *bufio.Reader) bool {
+	for {
+		_, err := reader.ReadSlice('\n')
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return false
+		}
+	}
+}
+
+func (l *TCPListener) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	l.closed = true
+	var err error
+	if l.listener != nil {
+		err = l.listener.Close()
+	}
+	for conn := range l.conns {
+		// Unblock pending reads; buffered lines are still flushed.
+		conn.SetReadDeadline(time.Now())
+	}
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	l.cancel()
+
+	return err
+}
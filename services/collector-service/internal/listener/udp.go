@@ -0,0 +1,132 @@
+package listener
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+)
+
+const (
+	maxDatagramSize = 64 * 1024
+
+	// queueSize is the number of datagrams waiting for the handler.
+	queueSize = 1024
+)
+
+// UDPListener treats every datagram as a batch of newline separated lines.
+// Datagrams are handled by a worker behind a bounded queue, so a slow
+// handler does not stall the reads and make the kernel drop packets
+// unnoticed; datagrams arriving while the queue is full are dropped and
+// counted instead.
+type UDPListener struct {
+	name    string
+	addr    string
+	handler LineHandler
+	log     logger.Logger
+	metrics *metrics.Metrics
+
+	mu     sync.Mutex
+	conn   net.PacketConn
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewUDP(name, addr string, handler LineHandler, log logger.Logger, m *metrics.Metrics) *UDPListener {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &UDPListener{
+		name:    name,
+		addr:    addr,
+		handler: handler,
+		log:     log,
+		metrics: m,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+}
+
+func (l *UDPListener) Name() string {
+	return l.name
+}
+
+func (l *UDPListener) Serve() error {
+	defer close(l.done)
+
+	conn, err := net.ListenPacket("udp", l.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s on %s: %w", l.name, l.addr, err)
+	}
+
+	l.mu.Lock()
+	l.conn = conn
+	l.mu.Unlock()
+
+	l.log.Info("UDP listener is ready to handle packets", "listener", l.name, "addr", l.addr)
+
+	// Datagrams still queued when the listener is closed are handled
+	// before Serve returns.
+	queue := make(chan []byte, queueSize)
+	workerDone := make(chan struct{})
+	go l.work(queue, workerDone)
+	defer func() {
+		close(queue)
+		<-workerDone
+	}()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			l.log.Warn("failed to read UDP packet", "listener", l.name, "error", err)
+			continue
+		}
+
+		select {
+		case queue <- bytes.Clone(buf[:n]):
+		default:
+			l.metrics.ListenerDroppedPackets.WithLabelValues(l.name).Inc()
+		}
+	}
+}
+
+func (l *UDPListener) work(queue <-chan []byte, done chan<- struct{}) {
+	defer close(done)
+
+	for payload := range queue {
+		if lines := ingest.SplitLines(payload); len(lines) > 0 {
+			l.handler(l.ctx, lines)
+		}
+	}
+}
+
+func (l *UDPListener) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	conn := l.conn
+	l.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	err := conn.Close()
+
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+	}
+	l.cancel()
+
+	return err
+}
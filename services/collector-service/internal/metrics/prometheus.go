@@ -9,6 +9,14 @@ type Metrics struct {
 	TotalRequests  prometheus.Counter
 	ResponseStatus prometheus.CounterVec
 	HttpDuration   prometheus.HistogramVec
+
+	IngestedMetrics        *prometheus.CounterVec
+	ListenerDroppedPackets *prometheus.CounterVec
+
+	SpoolDepthBatches prometheus.Gauge
+	SpoolDepthBytes   prometheus.Gauge
+	SpoolSegments     prometheus.Gauge
+	SpoolBatches      *prometheus.CounterVec
 }
 
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -25,5 +33,31 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "http_responce_seconds",
 			Help: "Duration of HTTP requests",
 		}, []string{"path"}),
+
+		IngestedMetrics: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "collector_ingested_metrics_total",
+			Help: "Number of metrics received through ingestion endpoints",
+		}, []string{"protocol", "status"}),
+		ListenerDroppedPackets: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "collector_listener_dropped_packets_total",
+			Help: "Number of UDP packets dropped because the listener's queue was full",
+		}, []string{"listener"}),
+
+		SpoolDepthBatches: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "collector_spool_depth_batches",
+			Help: "Number of batches waiting in the on-disk spool",
+		}),
+		SpoolDepthBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "collector_spool_depth_bytes",
+			Help: "Size of the on-disk spool segments in bytes",
+		}),
+		SpoolSegments: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "collector_spool_segments",
+			Help: "Number of on-disk spool segments",
+		}),
+		SpoolBatches: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "collector_spool_batches_total",
+			Help: "Number of batches passing through the on-disk spool",
+		}, []string{"event"}),
 	}
 }
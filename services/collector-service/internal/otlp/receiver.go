@@ -0,0 +1,98 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Receiver implements the OTLP metrics service. It backs both the gRPC
+// endpoint and the OTLP/HTTP handler.
+type Receiver struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	broker     broker.MessageBroker
+	translator *Translator
+	log        logger.Logger
+	metrics    *metrics.Metrics
+}
+
+func NewReceiver(broker broker.MessageBroker, translator *Translator, log logger.Logger, m *metrics.Metrics) *Receiver {
+	return &Receiver{
+		broker:     broker,
+		translator: translator,
+		log:        log,
+		metrics:    m,
+	}
+}
+
+func (r *Receiver) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	ctx = traceFromMetadata(ctx)
+
+	res := r.translator.Translate(req)
+	accepted := res.AcceptedPoints
+	rejected := res.RejectedPoints
+
+	if len(res.Metrics) > 0 {
+		if err := r.broker.SendMetrics(ctx, res.Metrics); err != nil {
+			var serErr *broker.SerialisationError
+			if !errors.As(err, &serErr) {
+				r.log.Error("failed to send otlp metrics", "error", err)
+				// Unavailable is retryable for OTLP exporters.
+				return nil, status.Error(codes.Unavailable, "failed to publish metrics")
+			}
+
+			r.log.Warn("some otlp metrics failed to be serialised", "error", serErr)
+			failed := res.FailedPoints(serErr.FailedIndexes)
+			accepted -= failed
+			rejected += failed
+		}
+	}
+
+	r.metrics.IngestedMetrics.WithLabelValues("otlp", "accepted").Add(float64(accepted))
+	r.metrics.IngestedMetrics.WithLabelValues("otlp", "rejected").Add(float64(rejected))
+
+	resp := &colmetricspb.ExportMetricsServiceResponse{}
+	if rejected > 0 {
+		r.log.Debug("otlp data points rejected", "count", rejected, "error", res.FirstError)
+
+		message := "failed to serialise metrics"
+		if res.FirstError != nil {
+			message = res.FirstError.Error()
+		}
+
+		resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: int64(rejected),
+			ErrorMessage:       message,
+		}
+	}
+
+	return resp, nil
+}
+
+// traceFromMetadata picks up the W3C trace context gRPC clients send as
+// metadata. The HTTP handler already has it in ctx from the server middleware.
+func traceFromMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	traceParent := md.Get(broker.TraceParentHeader)
+	if len(traceParent) == 0 {
+		return ctx
+	}
+
+	tc := broker.TraceContext{TraceParent: traceParent[0]}
+	if traceState := md.Get(broker.TraceStateHeader); len(traceState) > 0 {
+		tc.TraceState = traceState[0]
+	}
+
+	return broker.ContextWithTrace(ctx, tc)
+}
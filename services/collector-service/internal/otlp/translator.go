@@ -0,0 +1,367 @@
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+const (
+	defaultSourceAttribute = "service.name"
+	defaultSource          = "otlp"
+	bucketBoundLabel       = "le"
+	quantileLabel          = "quantile"
+)
+
+// Translator flattens OTLP metrics into the platform's metric stream.
+// Histograms and summaries are expanded Prometheus-style into _count, _sum,
+// _min, _max, _bucket and quantile series.
+type Translator struct {
+	sourceAttribute string
+	defaultSource   string
+	validator       *ingest.Validator
+}
+
+// TranslateResult holds the metrics produced from a request together with the
+// data points that were dropped because one of their series failed validation.
+// PointIndexes maps every metric to the accepted data point it was expanded
+// from, numbered from 0 up to AcceptedPoints.
+type TranslateResult struct {
+	Metrics        []models.Metric
+	PointIndexes   []int
+	AcceptedPoints int
+	RejectedPoints int
+	FirstError     error
+}
+
+// FailedPoints returns the number of data points with at least one of the
+// metrics at the given indexes, such as those that failed to serialise.
+func (r TranslateResult) FailedPoints(metricIndexes []int) int {
+	failed := make(map[int]struct{}, len(metricIndexes))
+	for _, i := range metricIndexes {
+		failed[r.PointIndexes[i]] = struct{}{}
+	}
+
+	return len(failed)
+}
+
+type point struct {
+	labels      map[string]any
+	collectedAt time.Time
+	series      []models.Metric
+}
+
+func NewTranslator(sourceAttribute, defaultSrc string, validator *ingest.Validator) *Translator {
+	if sourceAttribute == "" {
+		sourceAttribute = defaultSourceAttribute
+	}
+	if defaultSrc == "" {
+		defaultSrc = defaultSource
+	}
+
+	return &Translator{
+		sourceAttribute: sourceAttribute,
+		defaultSource:   defaultSrc,
+		validator:       validator,
+	}
+}
+
+func (t *Translator) Translate(req *colmetricspb.ExportMetricsServiceRequest) TranslateResult {
+	var res TranslateResult
+
+	for _, rm := range req.GetResourceMetrics() {
+		source := t.defaultSource
+		resourceLabels := make(map[string]any)
+
+		for _, kv := range rm.GetResource().GetAttributes() {
+			if kv.GetKey() == t.sourceAttribute {
+				if v := anyValueString(kv.GetValue()); v != "" {
+					source = v
+				}
+				continue
+			}
+			resourceLabels[ingest.SanitizeLabelKey(kv.GetKey())] = anyValueString(kv.GetValue())
+		}
+
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				for _, p := range t.points(m, resourceLabels) {
+					t.collect(&res, source, m, p)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// collect validates every series of a data point and keeps the point only if
+// all of them pass, so a histogram is never stored with missing buckets.
+func (t *Translator) collect(res *TranslateResult, source string, m *metricspb.Metric, p point) {
+	for i := range p.series {
+		p.series[i].Source = source
+		p.series[i].CollectedAt = p.collectedAt
+		p.series[i].Description = m.GetDescription()
+		if !isCountSeries(m.GetName(), p.series[i].Name) {
+			p.series[i].Unit = m.GetUnit()
+		}
+
+		if err := t.validator.Validate(&p.series[i]); err != nil {
+			res.RejectedPoints++
+			if res.FirstError == nil {
+				res.FirstError = fmt.Errorf("metric %q: %w", p.series[i].Name, err)
+			}
+			return
+		}
+	}
+
+	res.Metrics = append(res.Metrics, p.series...)
+	for range p.series {
+		res.PointIndexes = append(res.PointIndexes, res.AcceptedPoints)
+	}
+	res.AcceptedPoints++
+}
+
+// isCountSeries reports whether the series derived from a histogram or
+// summary holds observation counts, which do not have the metric's unit.
+func isCountSeries(metric, series string) bool {
+	return series == metric+"_count" || series == metric+"_bucket"
+}
+
+func (t *Translator) points(m *metricspb.Metric, resourceLabels map[string]any) []point {
+	var points []point
+
+	switch data := m.GetData().(type) {
+	case *metricspb.Metric_Gauge:
+		for _, dp := range data.Gauge.GetDataPoints() {
+			if p, ok := numberPoint(m.GetName(), dp, resourceLabels); ok {
+				points = append(points, p)
+			}
+		}
+	case *metricspb.Metric_Sum:
+		for _, dp := range data.Sum.GetDataPoints() {
+			if p, ok := numberPoint(m.GetName(), dp, resourceLabels); ok {
+				points = append(points, p)
+			}
+		}
+	case *metricspb.Metric_Histogram:
+		for _, dp := range data.Histogram.GetDataPoints() {
+			if noRecordedValue(dp.GetFlags()) {
+				continue
+			}
+			points = append(points, histogramPoint(m.GetName(), dp, resourceLabels))
+		}
+	case *metricspb.Metric_ExponentialHistogram:
+		for _, dp := range data.ExponentialHistogram.GetDataPoints() {
+			if noRecordedValue(dp.GetFlags()) {
+				continue
+			}
+			points = append(points, exponentialHistogramPoint(m.GetName(), dp, resourceLabels))
+		}
+	case *metricspb.Metric_Summary:
+		for _, dp := range data.Summary.GetDataPoints() {
+			if noRecordedValue(dp.GetFlags()) {
+				continue
+			}
+			points = append(points, summaryPoint(m.GetName(), dp, resourceLabels))
+		}
+	}
+
+	return points
+}
+
+func numberPoint(name string, dp *metricspb.NumberDataPoint, resourceLabels map[string]any) (point, bool) {
+	if noRecordedValue(dp.GetFlags()) {
+		return point{}, false
+	}
+
+	var value float64
+	switch v := dp.GetValue().(type) {
+	case *metricspb.NumberDataPoint_AsDouble:
+		value = v.AsDouble
+	case *metricspb.NumberDataPoint_AsInt:
+		value = float64(v.AsInt)
+	default:
+		return point{}, false
+	}
+
+	p := newPoint(dp.GetAttributes(), dp.GetTimeUnixNano(), resourceLabels)
+	p.add(name, value, nil)
+
+	return p, true
+}
+
+func histogramPoint(name string, dp *metricspb.HistogramDataPoint, resourceLabels map[string]any) point {
+	p := newPoint(dp.GetAttributes(), dp.GetTimeUnixNano(), resourceLabels)
+	p.addAggregates(name, dp.GetCount(), dp.Sum, dp.Min, dp.Max)
+
+	bounds := dp.GetExplicitBounds()
+	var cumulative uint64
+	for i, count := range dp.GetBucketCounts() {
+		cumulative += count
+		le := math.Inf(1)
+		if i < len(bounds) {
+			le = bounds[i]
+		}
+		p.add(name+"_bucket", float64(cumulative), map[string]any{bucketBoundLabel: formatBound(le)})
+	}
+
+	return p
+}
+
+// exponentialHistogramPoint turns the base-2 exponential buckets into
+// cumulative "le" buckets. With base = 2^(2^-scale), positive bucket i covers
+// (base^i, base^(i+1)] and negative bucket i covers [-base^(i+1), -base^i).
+func exponentialHistogramPoint(name string, dp *metricspb.ExponentialHistogramDataPoint, resourceLabels map[string]any) point {
+	p := newPoint(dp.GetAttributes(), dp.GetTimeUnixNano(), resourceLabels)
+	p.addAggregates(name, dp.GetCount(), dp.Sum, dp.Min, dp.Max)
+
+	scale := dp.GetScale()
+	var cumulative uint64
+
+	negative := dp.GetNegative()
+	negCounts := negative.GetBucketCounts()
+	for i := len(negCounts) - 1; i >= 0; i-- {
+		cumulative += negCounts[i]
+		upper := -exponentialBound(negative.GetOffset()+int32(i), scale)
+		p.add(name+"_bucket", float64(cumulative), map[string]any{bucketBoundLabel: formatBound(upper)})
+	}
+
+	cumulative += dp.GetZeroCount()
+	p.add(name+"_bucket", float64(cumulative), map[string]any{bucketBoundLabel: formatBound(dp.GetZeroThreshold())})
+
+	positive := dp.GetPositive()
+	for i, count := range positive.GetBucketCounts() {
+		cumulative += count
+		upper := exponentialBound(positive.GetOffset()+int32(i)+1, scale)
+		p.add(name+"_bucket", float64(cumulative), map[string]any{bucketBoundLabel: formatBound(upper)})
+	}
+
+	p.add(name+"_bucket", float64(dp.GetCount()), map[string]any{bucketBoundLabel: formatBound(math.Inf(1))})
+
+	return p
+}
+
+func summaryPoint(name string, dp *metricspb.SummaryDataPoint, resourceLabels map[string]any) point {
+	p := newPoint(dp.GetAttributes(), dp.GetTimeUnixNano(), resourceLabels)
+	sum := dp.GetSum()
+	p.addAggregates(name, dp.GetCount(), &sum, nil, nil)
+
+	for _, q := range dp.GetQuantileValues() {
+		p.add(name, q.GetValue(), map[string]any{quantileLabel: formatBound(q.GetQuantile())})
+	}
+
+	return p
+}
+
+func newPoint(attrs []*commonpb.KeyValue, timeUnixNano uint64, resourceLabels map[string]any) point {
+	labels := make(map[string]any, len(resourceLabels)+len(attrs))
+	for k, v := range resourceLabels {
+		labels[k] = v
+	}
+	for _, kv := range attrs {
+		labels[ingest.SanitizeLabelKey(kv.GetKey())] = anyValueString(kv.GetValue())
+	}
+
+	var collectedAt time.Time
+	if timeUnixNano > 0 {
+		collectedAt = time.Unix(0, int64(timeUnixNano))
+	}
+
+	return point{labels: labels, collectedAt: collectedAt}
+}
+
+func (p *point) add(name string, value float64, extra map[string]any) {
+	labels := p.labels
+	if len(extra) > 0 {
+		labels = make(map[string]any, len(p.labels)+len(extra))
+		for k, v := range p.labels {
+			labels[k] = v
+		}
+		for k, v := range extra {
+			labels[k] = v
+		}
+	}
+
+	p.series = append(p.series, models.Metric{
+		Name:   name,
+		Value:  value,
+		Labels: labels,
+	})
+}
+
+func (p *point) addAggregates(name string, count uint64, sum, min, max *float64) {
+	p.add(name+"_count", float64(count), nil)
+	if sum != nil {
+		p.add(name+"_sum", *sum, nil)
+	}
+	if min != nil {
+		p.add(name+"_min", *min, nil)
+	}
+	if max != nil {
+		p.add(name+"_max", *max, nil)
+	}
+}
+
+func exponentialBound(index, scale int32) float64 {
+	return math.Exp2(float64(index) * math.Exp2(-float64(scale)))
+}
+
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func noRecordedValue(flags uint32) bool {
+	return flags&uint32(metricspb.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK) != 0
+}
+
+func anyValueString(v *commonpb.AnyValue) string {
+	switch val := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return val.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(val.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(val.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(val.DoubleValue, 'g', -1, 64)
+	case *commonpb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(val.BytesValue)
+	case *commonpb.AnyValue_ArrayValue, *commonpb.AnyValue_KvlistValue:
+		data, err := json.Marshal(anyValueInterface(v))
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	default:
+		return ""
+	}
+}
+
+func anyValueInterface(v *commonpb.AnyValue) any {
+	switch val := v.GetValue().(type) {
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]any, 0, len(val.ArrayValue.GetValues()))
+		for _, item := range val.ArrayValue.GetValues() {
+			values = append(values, anyValueInterface(item))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		values := make(map[string]any, len(val.KvlistValue.GetValues()))
+		for _, kv := range val.KvlistValue.GetValues() {
+			values[kv.GetKey()] = anyValueInterface(kv.GetValue())
+		}
+		return values
+	default:
+		return anyValueString(v)
+	}
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// readBody reads the whole request body, transparently decompressing gzip.
+// The limit applies to both the wire and the decompressed size. On failure
+// the error response has already been written and ok is false.
+func readBody(w http.ResponseWriter, r *http.Request, maxBytes int64) (data []byte, ok bool) {
+	body := io.Reader(http.MaxBytesReader(w, r.Body, maxBytes))
+
+	switch r.Header.Get("Content-Encoding") {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(w, "invalid gzip payload", http.StatusBadRequest)
+			return nil, false
+		}
+		defer gz.Close()
+		body = io.LimitReader(gz, maxBytes+1)
+	default:
+		http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("request body exceeds %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if int64(len(data)) > maxBytes {
+		http.Error(w, fmt.Sprintf("decompressed body exceeds %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+
+	return data, true
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/influx"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+)
+
+const defaultInfluxMaxBodyBytes = 8 << 20
+
+type influxHandler struct {
+	ingester     *ingest.LineIngester
+	precision    time.Duration
+	maxBodyBytes int64
+	log          logger.Logger
+}
+
+type influxErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func newInfluxHandler(ingester *ingest.LineIngester, precision time.Duration, maxBodyBytes int64, log logger.Logger) *influxHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultInfluxMaxBodyBytes
+	}
+
+	return &influxHandler{
+		ingester:     ingester,
+		precision:    precision,
+		maxBodyBytes: maxBodyBytes,
+		log:          log,
+	}
+}
+
+// ServeHTTP implements the InfluxDB 1.x /write endpoint. The db and rp
+// parameters are accepted for compatibility and ignored.
+func (h influxHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	precision := h.precision
+	if p := r.URL.Query().Get("precision"); p != "" {
+		var err error
+		if precision, err = influx.ParsePrecision(p); err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	data, ok := readBody(w, r, h.maxBodyBytes)
+	if !ok {
+		return
+	}
+
+	res, err := h.ingester.Ingest(r.Context(), ingest.SplitLines(data), influx.NewParser(precision).Parse)
+	if err != nil {
+		h.log.Error("failed to send influx points", "error", err)
+		h.writeError(w, http.StatusServiceUnavailable, "failed to publish points")
+		return
+	}
+
+	if res.Rejected > 0 {
+		// Matches InfluxDB: valid points are kept and the write is reported as partial.
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("partial write: %v dropped=%d", res.Errors[0], res.Rejected))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h influxHandler) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(influxErrorResponse{Error: message}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
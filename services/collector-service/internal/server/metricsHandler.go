@@ -2,55 +2,159 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
 )
 
+const defaultMaxBodyBytes = 1 << 20
+
 type metricsHandler struct {
-	broker  broker.MessageBroker
-	log     logger.Logger
-	metrics *metrics.Metrics
+	broker       broker.MessageBroker
+	validator    *ingest.Validator
+	maxBodyBytes int64
+	log          logger.Logger
+	metrics      *metrics.Metrics
+}
+
+type metricRequest struct {
+	Source      string         `json:"source"`
+	Name        string         `json:"name"`
+	Value       *float64       `json:"value"`
+	Labels      map[string]any `json:"labels"`
+	CollectedAt time.Time      `json:"collected_at"`
+}
+
+type rejectedMetric struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
 }
 
-func newMetricsHandler(broker broker.MessageBroker, log logger.Logger, m *metrics.Metrics) *metricsHandler {
+type ingestResponse struct {
+	Message       string           `json:"message"`
+	AcceptedCount int              `json:"accepted_count"`
+	RejectedCount int              `json:"rejected_count"`
+	Rejected      []rejectedMetric `json:"rejected,omitempty"`
+}
+
+func newMetricsHandler(broker broker.MessageBroker, validator *ingest.Validator, maxBodyBytes int64, log logger.Logger, m *metrics.Metrics) *metricsHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+
 	return &metricsHandler{
-		broker:  broker,
-		log:     log,
-		metrics: m,
+		broker:       broker,
+		validator:    validator,
+		maxBodyBytes: maxBodyBytes,
+		log:          log,
+		metrics:      m,
 	}
 }
 
 func (h metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var metrics []models.Metric
-	if err := json.NewDecoder(r.Body); err != nil {
-		http.Error(w, "invalid request body", http.StatusInternalServerError)
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+
+	var items []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("request body exceeds %d bytes", h.maxBodyBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, "invalid request body: expected a JSON array of metrics", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.broker.SendMetrics(r.Context(), metrics); err != nil {
-		if batchErr, ok := err.(*broker.SerialisationError); ok {
-			h.log.Warn("some metrics failed to be serialised", "error", batchErr)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusMultiStatus)
-			json.NewEncoder(w).Encode(map[string]any{
-				"message":           "Request processed with some failures",
-				"successfull_count": batchErr.SuccessfullCount,
-				"failure_count":     batchErr.FailedCount,
-			})
+	if len(items) == 0 {
+		http.Error(w, "request body contains no metrics", http.StatusBadRequest)
+		return
+	}
 
-			return
+	var rejected []rejectedMetric
+	accepted := make([]models.Metric, 0, len(items))
+	acceptedIndexes := make([]int, 0, len(items))
+
+	for i, item := range items {
+		var req metricRequest
+		if err := json.Unmarshal(item, &req); err != nil {
+			rejected = append(rejected, rejectedMetric{Index: i, Reason: "malformed metric: " + err.Error()})
+			continue
 		}
 
-		h.log.Error("failed to send metrics", "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		if req.Value == nil {
+			rejected = append(rejected, rejectedMetric{Index: i, Reason: "value is required"})
+			continue
+		}
 
-		return
+		metric := models.Metric{
+			Source:      req.Source,
+			Name:        req.Name,
+			Value:       *req.Value,
+			Labels:      req.Labels,
+			CollectedAt: req.CollectedAt,
+		}
+
+		if err := h.validator.Validate(&metric); err != nil {
+			rejected = append(rejected, rejectedMetric{Index: i, Reason: err.Error()})
+			continue
+		}
+
+		accepted = append(accepted, metric)
+		acceptedIndexes = append(acceptedIndexes, i)
+	}
+
+	if len(accepted) > 0 {
+		if err := h.broker.SendMetrics(r.Context(), accepted); err != nil {
+			var serErr *broker.SerialisationError
+			if !errors.As(err, &serErr) {
+				h.log.Error("failed to send metrics", "error", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			h.log.Warn("some metrics failed to be serialised", "error", serErr)
+			for j, idx := range serErr.FailedIndexes {
+				reason := "failed to serialise metric"
+				if j < len(serErr.Errors) {
+					reason = serErr.Errors[j].Error()
+				}
+				rejected = append(rejected, rejectedMetric{Index: acceptedIndexes[idx], Reason: reason})
+			}
+		}
+	}
+
+	resp := ingestResponse{
+		AcceptedCount: len(items) - len(rejected),
+		RejectedCount: len(rejected),
+		Rejected:      rejected,
 	}
 
-	w.WriteHeader(http.StatusAccepted)
-	w.Write([]byte("Metrics accepted"))
+	h.metrics.IngestedMetrics.WithLabelValues("json", "accepted").Add(float64(resp.AcceptedCount))
+	h.metrics.IngestedMetrics.WithLabelValues("json", "rejected").Add(float64(resp.RejectedCount))
+
+	status := http.StatusAccepted
+	switch {
+	case resp.AcceptedCount == 0:
+		resp.Message = "All metrics were rejected"
+		status = http.StatusUnprocessableEntity
+	case resp.RejectedCount > 0:
+		resp.Message = "Request processed with some failures"
+		status = http.StatusMultiStatus
+	default:
+		resp.Message = "Metrics accepted"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
 }
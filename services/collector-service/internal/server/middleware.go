@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
 )
 
@@ -27,6 +28,21 @@ func recoverMiddleware(next http.Handler, log logger.Logger) http.Handler {
 	})
 }
 
+// traceMiddleware carries the caller's W3C trace context into the request
+// context, from where the broker forwards it with the produced messages.
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if traceParent := r.Header.Get(broker.TraceParentHeader); traceParent != "" {
+			ctx := broker.ContextWithTrace(r.Context(), broker.TraceContext{
+				TraceParent: traceParent,
+				TraceState:  r.Header.Get(broker.TraceStateHeader),
+			})
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func prometheusMiddleware(next http.Handler, m *metrics.Metrics) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/otlp"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultOTLPMaxBodyBytes = 16 << 20
+	contentTypeProtobuf     = "application/x-protobuf"
+	contentTypeJSON         = "application/json"
+)
+
+type otlpHandler struct {
+	receiver     *otlp.Receiver
+	maxBodyBytes int64
+	log          logger.Logger
+}
+
+func newOTLPHandler(receiver *otlp.Receiver, maxBodyBytes int64, log logger.Logger) *otlpHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultOTLPMaxBodyBytes
+	}
+
+	return &otlpHandler{
+		receiver:     receiver,
+		maxBodyBytes: maxBodyBytes,
+		log:          log,
+	}
+}
+
+func (h otlpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (contentType != contentTypeProtobuf && contentType != contentTypeJSON) {
+		http.Error(w, "unsupported content type, expected "+contentTypeProtobuf+" or "+contentTypeJSON, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	data, ok := readBody(w, r, h.maxBodyBytes)
+	if !ok {
+		return
+	}
+
+	var req colmetricspb.ExportMetricsServiceRequest
+	if contentType == contentTypeJSON {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, &req)
+	} else {
+		err = protobuf.Unmarshal(data, &req)
+	}
+	if err != nil {
+		http.Error(w, "invalid otlp metrics request", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.receiver.Export(r.Context(), &req)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if status.Code(err) == codes.Unavailable {
+			code = http.StatusServiceUnavailable
+		}
+		http.Error(w, status.Convert(err).Message(), code)
+		return
+	}
+
+	var out []byte
+	if contentType == contentTypeJSON {
+		out, err = protojson.Marshal(resp)
+	} else {
+		out, err = protobuf.Marshal(resp)
+	}
+	if err != nil {
+		h.log.Error("failed to encode otlp response", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(out); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/proto"
+	"github.com/klauspost/compress/snappy"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultRemoteWriteMaxBodyBytes = 32 << 20
+	defaultRemoteWriteSourceLabel  = "job"
+	defaultRemoteWriteSource       = "prometheus"
+	// Prometheus keeps about two hours of samples in its WAL and resends
+	// them after an outage, so they may be older than other ingest accepts.
+	defaultRemoteWriteMaxPastSkew = 6 * time.Hour
+	metricNameLabel               = "__name__"
+)
+
+type remoteWriteHandler struct {
+	broker        broker.MessageBroker
+	validator     *ingest.Validator
+	sourceLabel   string
+	defaultSource string
+	maxBodyBytes  int64
+	log           logger.Logger
+	metrics       *metrics.Metrics
+}
+
+func newRemoteWriteHandler(ingestCfg config.IngestConfig, broker broker.MessageBroker, log logger.Logger, m *metrics.Metrics) *remoteWriteHandler {
+	cfg := ingestCfg.RemoteWrite
+
+	validatorCfg := ingestCfg
+	validatorCfg.MaxPastSkew = cfg.MaxPastSkew
+	if validatorCfg.MaxPastSkew <= 0 {
+		validatorCfg.MaxPastSkew = defaultRemoteWriteMaxPastSkew
+	}
+
+	h := &remoteWriteHandler{
+		broker:        broker,
+		validator:     ingest.NewValidator(validatorCfg),
+		sourceLabel:   cfg.SourceLabel,
+		defaultSource: cfg.DefaultSource,
+		maxBodyBytes:  cfg.MaxBodyBytes,
+		log:           log,
+		metrics:       m,
+	}
+
+	if h.sourceLabel == "" {
+		h.sourceLabel = defaultRemoteWriteSourceLabel
+	}
+	if h.defaultSource == "" {
+		h.defaultSource = defaultRemoteWriteSource
+	}
+	if h.maxBodyBytes <= 0 {
+		h.maxBodyBytes = defaultRemoteWriteMaxBodyBytes
+	}
+
+	return h
+}
+
+func (h remoteWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	compressed, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("request body exceeds %d bytes", h.maxBodyBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	decodedLen, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		http.Error(w, "invalid snappy payload", http.StatusBadRequest)
+		return
+	}
+	if int64(decodedLen) > h.maxBodyBytes {
+		http.Error(w, fmt.Sprintf("decompressed body exceeds %d bytes", h.maxBodyBytes), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, "invalid snappy payload", http.StatusBadRequest)
+		return
+	}
+
+	var req proto.WriteRequest
+	if err := protobuf.Unmarshal(data, &req); err != nil {
+		http.Error(w, "invalid remote write request", http.StatusBadRequest)
+		return
+	}
+
+	batch, rejected, rejectErr := h.toMetrics(&req)
+	accepted := len(batch)
+
+	if len(batch) > 0 {
+		if err := h.broker.SendMetrics(r.Context(), batch); err != nil {
+			var serErr *broker.SerialisationError
+			if !errors.As(err, &serErr) {
+				h.log.Error("failed to send remote write samples", "error", err)
+				// 5xx tells Prometheus to retry the batch later.
+				http.Error(w, "failed to publish samples", http.StatusServiceUnavailable)
+				return
+			}
+
+			h.log.Warn("some remote write samples failed to be serialised", "error", serErr)
+			accepted -= serErr.FailedCount
+			rejected += serErr.FailedCount
+			if rejectErr == nil {
+				rejectErr = serErr
+			}
+		}
+	}
+
+	h.metrics.IngestedMetrics.WithLabelValues("remote_write", "accepted").Add(float64(accepted))
+	h.metrics.IngestedMetrics.WithLabelValues("remote_write", "rejected").Add(float64(rejected))
+
+	if rejected > 0 {
+		h.log.Debug("remote write samples rejected", "count", rejected, "error", rejectErr)
+		// The accepted samples are already published. 4xx tells Prometheus
+		// not to retry the batch, since the rejected ones would fail again.
+		http.Error(w, fmt.Sprintf("%d of %d samples rejected: %v", rejected, accepted+rejected, rejectErr), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toMetrics returns the valid samples, the number of invalid ones and the
+// first validation error.
+func (h remoteWriteHandler) toMetrics(req *proto.WriteRequest) ([]models.Metric, int, error) {
+	var batch []models.Metric
+	var firstErr error
+	rejected := 0
+
+	for _, ts := range req.GetTimeseries() {
+		name := ""
+		source := h.defaultSource
+		labels := make(map[string]any, len(ts.GetLabels()))
+
+		for _, l := range ts.GetLabels() {
+			switch l.GetName() {
+			case metricNameLabel:
+				name = l.GetValue()
+			case h.sourceLabel:
+				source = l.GetValue()
+			default:
+				labels[l.GetName()] = l.GetValue()
+			}
+		}
+
+		for _, s := range ts.GetSamples() {
+			// NaN values are staleness markers, not real samples.
+			if math.IsNaN(s.GetValue()) {
+				continue
+			}
+
+			metric := models.Metric{
+				Source:      source,
+				Name:        name,
+				Value:       s.GetValue(),
+				Labels:      labels,
+				CollectedAt: time.UnixMilli(s.GetTimestamp()),
+			}
+
+			if err := h.validator.Validate(&metric); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("series '%s': %w", name, err)
+				}
+				rejected++
+				continue
+			}
+
+			batch = append(batch, metric)
+		}
+	}
+
+	return batch, rejected, firstErr
+}
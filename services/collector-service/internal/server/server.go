@@ -3,38 +3,97 @@ package server
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/graphite"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/influx"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/listener"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/otlp"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/statsd"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
 	httpServer *http.Server
+	listeners  []listener.Listener
 	log        logger.Logger
 }
 
-func New(cfg config.ServerConfig, log logger.Logger, broker broker.MessageBroker) *Server {
+func New(cfg config.ServerConfig, ingestCfg config.IngestConfig, log logger.Logger, broker broker.MessageBroker, reg *prometheus.Registry, m *metrics.Metrics) *Server {
 	mux := http.NewServeMux()
 
-	reg := prometheus.NewRegistry()
-	m := metrics.NewMetrics(reg)
-	reg.MustRegister(collectors.NewGoCollector())
-
 	apiRouter := http.NewServeMux()
-	apiRouter.Handle("POST /v1/metrics", newMetricsHandler(broker, log, m))
+	validator := ingest.NewValidator(ingestCfg)
+	apiRouter.Handle("POST /v1/metrics", newMetricsHandler(broker, validator, ingestCfg.MaxBodyBytes, log, m))
+
+	if ingestCfg.RemoteWrite.Enabled {
+		apiRouter.Handle("POST /v1/write", newRemoteWriteHandler(ingestCfg, broker, log, m))
+	}
 
 	apiHandler := recoverMiddleware(apiRouter, log)
 	mux.Handle("/api/", http.StripPrefix("/api", apiHandler))
 
+	var listeners []listener.Listener
+
+	if ingestCfg.OTLP.Enabled {
+		otlpCfg := ingestCfg.OTLP
+		translator := otlp.NewTranslator(otlpCfg.SourceAttribute, otlpCfg.DefaultSource, validator)
+		receiver := otlp.NewReceiver(broker, translator, log, m)
+
+		// OTLP/HTTP exporters post to /v1/metrics on the base endpoint by default.
+		otlpHandler := newOTLPHandler(receiver, otlpCfg.MaxBodyBytes, log)
+		mux.Handle("POST /v1/metrics", recoverMiddleware(otlpHandler, log))
+
+		if otlpCfg.GRPCPort != "" {
+			grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(int(otlpHandler.maxBodyBytes)))
+			colmetricspb.RegisterMetricsServiceServer(grpcServer, receiver)
+			listeners = append(listeners, listener.NewGRPC("otlp", ":"+otlpCfg.GRPCPort, grpcServer, log))
+		}
+	}
+
+	if ingestCfg.Influx.Enabled {
+		influxCfg := ingestCfg.Influx
+		ingester := ingest.NewLineIngester("influx", validator, broker, log, m)
+
+		precision, err := influx.ParsePrecision(influxCfg.Precision)
+		if err != nil {
+			log.Warn("invalid influx precision, falling back to nanoseconds", "error", err)
+			precision = time.Nanosecond
+		}
+
+		// InfluxDB 1.x clients write to /write on the base URL.
+		mux.Handle("POST /write", recoverMiddleware(newInfluxHandler(ingester, precision, influxCfg.MaxBodyBytes, log), log))
+
+		if influxCfg.UDPPort != "" {
+			parser := influx.NewParser(precision)
+			listeners = append(listeners, listener.NewUDP("influx", ":"+influxCfg.UDPPort, lineHandler(ingester, parser.Parse, log), log, m))
+		}
+	}
+
+	if ingestCfg.Graphite.Enabled && ingestCfg.Graphite.TCPPort != "" {
+		graphiteCfg := ingestCfg.Graphite
+		ingester := ingest.NewLineIngester("graphite", validator, broker, log, m)
+		parser := graphite.NewParser(graphiteCfg.DefaultSource)
+		listeners = append(listeners, listener.NewTCP("graphite", ":"+graphiteCfg.TCPPort, graphiteCfg.IdleTimeout, lineHandler(ingester, parser.Parse, log), log))
+	}
+
+	if ingestCfg.StatsD.Enabled && ingestCfg.StatsD.UDPPort != "" {
+		statsdCfg := ingestCfg.StatsD
+		listeners = append(listeners, statsd.NewServer(":"+statsdCfg.UDPPort, statsdCfg.FlushInterval, statsdCfg.DefaultSource, statsdCfg.MaxTimerSamples, validator, broker, log, m))
+	}
+
 	mux.HandleFunc("/healthz", healthCheckHandler)
 	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
 
-	finalHandler := prometheusMiddleware(mux, m)
+	finalHandler := prometheusMiddleware(traceMiddleware(mux), m)
 
 	return &Server{
 		httpServer: &http.Server{
@@ -44,7 +103,8 @@ func New(cfg config.ServerConfig, log logger.Logger, broker broker.MessageBroker
 			WriteTimeout: cfg.Timeout,
 			IdleTimeout:  cfg.IdleTimeout,
 		},
-		log: log,
+		listeners: listeners,
+		log:       log,
 	}
 }
 
@@ -53,10 +113,43 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// StartListeners serves the protocol listeners next to the HTTP server and
+// returns the first error any of them fails with.
+func (s *Server) StartListeners() error {
+	errCh := make(chan error, len(s.listeners))
+	for _, l := range s.listeners {
+		go func() {
+			errCh <- l.Serve()
+		}()
+	}
+
+	for range s.listeners {
+		if err := <-errCh; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
+	for _, l := range s.listeners {
+		if err := l.Shutdown(ctx); err != nil {
+			s.log.Error("listener shutdown failed", "listener", l.Name(), "error", err)
+		}
+	}
+
 	return s.httpServer.Shutdown(ctx)
 }
 
+func lineHandler(ingester *ingest.LineIngester, parse ingest.LineParser, log logger.Logger) listener.LineHandler {
+	return func(ctx context.Context, lines []ingest.Line) {
+		if _, err := ingester.Ingest(ctx, lines, parse); err != nil {
+			log.Error("failed to send metrics", "error", err)
+		}
+	}
+}
+
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
@@ -0,0 +1,30 @@
+package source
+
+import "fmt"
+
+type UnknownTypeError struct {
+	Type string
+}
+
+func (e UnknownTypeError) Error() string {
+	return fmt.Sprintf("unknown source type '%s', available types: %v", e.Type, Types())
+}
+
+type MissingParamError struct {
+	Source string
+	Param  string
+}
+
+func (e MissingParamError) Error() string {
+	return fmt.Sprintf("source '%s': missing required param '%s'", e.Source, e.Param)
+}
+
+type InvalidParamError struct {
+	Source string
+	Param  string
+	Reason string
+}
+
+func (e InvalidParamError) Error() string {
+	return fmt.Sprintf("source '%s': invalid param '%s': %s", e.Source, e.Param, e.Reason)
+}
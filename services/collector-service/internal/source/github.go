@@ -0,0 +1,205 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/client"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+func init() {
+	Register("github", newGithubSource)
+}
+
+type githubParams struct {
+	Repositories          []string      `mapstructure:"repositories"`
+	BaseURL               string        `mapstructure:"base_url"`
+	MinRateLimitRemaining int           `mapstructure:"min_rate_limit_remaining"`
+	ActivityWindow        time.Duration `mapstructure:"activity_window"`
+	// WorkflowMaxPages bounds the workflow runs fetched per poll. With more
+	// runs in the window the success rate covers only the newest of them.
+	WorkflowMaxPages int `mapstructure:"workflow_max_pages"`
+}
+
+type GithubSource struct {
+	name             string
+	repositories     []string
+	activityWindow   time.Duration
+	workflowMaxPages int
+	client           *client.GithubClient
+}
+
+func newGithubSource(cfg config.SourceConfig, global *config.Config) (Source, error) {
+	params := githubParams{
+		MinRateLimitRemaining: 10,
+		ActivityWindow:        7 * 24 * time.Hour,
+		WorkflowMaxPages:      3,
+	}
+	if err := decodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+
+	if len(params.Repositories) == 0 {
+		return nil, MissingParamError{Source: cfg.Name, Param: "repositories"}
+	}
+
+	return &GithubSource{
+		name:             cfg.Name,
+		repositories:     params.Repositories,
+		activityWindow:   params.ActivityWindow,
+		workflowMaxPages: params.WorkflowMaxPages,
+		client:           client.NewGithubClient(params.BaseURL, global.Github.Token, params.MinRateLimitRemaining),
+	}, nil
+}
+
+func (s *GithubSource) Name() string {
+	return s.name
+}
+
+func (s *GithubSource) CacheKey() (CacheKey, bool) {
+	if len(s.repositories) != 1 {
+		return CacheKey{}, false
+	}
+
+	return CacheKey{
+		Source: "GitHub",
+		Name:   "stargazers_count",
+		Labels: map[string]string{"repository": s.repositories[0]},
+	}, true
+}
+
+func (s *GithubSource) Collect(ctx context.Context) ([]models.Metric, error) {
+	var metrics []models.Metric
+	var errs []error
+
+	for _, repo := range s.repositories {
+		repoMetrics, err := s.collectRepo(ctx, repo)
+		metrics = append(metrics, repoMetrics...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("repository %s: %w", repo, err))
+		}
+
+		var rateErr client.RateLimitError
+		if errors.As(err, &rateErr) {
+			break
+		}
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+func (s *GithubSource) collectRepo(ctx context.Context, repo string) ([]models.Metric, error) {
+	now := time.Now()
+	window := formatWindow(s.activityWindow)
+	// Truncating keeps request URLs stable between polls so ETags can match.
+	since := now.Add(-s.activityWindow).Truncate(time.Hour)
+
+	newMetric := func(name string, value float64, extraLabels ...string) models.Metric {
+		labels := map[string]any{"repository": repo}
+		for i := 0; i+1 < len(extraLabels); i += 2 {
+			labels[extraLabels[i]] = extraLabels[i+1]
+		}
+
+		return models.Metric{
+			Source:      "GitHub",
+			Name:        name,
+			Value:       value,
+			Labels:      labels,
+			CollectedAt: now,
+		}
+	}
+
+	info, err := s.client.GetRepoInfo(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo info: %w", err)
+	}
+
+	metrics := []models.Metric{
+		newMetric("stargazers_count", float64(info.StargazersCount)),
+		newMetric("forks_count", float64(info.ForksCount)),
+		newMetric("watchers_count", float64(info.SubscribersCount)),
+	}
+
+	var errs []error
+
+	// open_issues_count reported by GitHub includes pull requests.
+	openPRs, err := s.client.CountOpenPullRequests(ctx, repo)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to count open pull requests: %w", err))
+		metrics = append(metrics, newMetric("open_issues_count", float64(info.OpenIssuesCount)))
+	} else {
+		metrics = append(metrics,
+			newMetric("open_pull_requests_count", float64(openPRs)),
+			newMetric("open_issues_count", float64(max(info.OpenIssuesCount-openPRs, 0))),
+		)
+	}
+
+	release, err := s.client.GetLatestRelease(ctx, repo)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get latest release: %w", err))
+	} else if release != nil && !release.PublishedAt.IsZero() {
+		ageDays := math.Round(now.Sub(release.PublishedAt).Hours()/24*100) / 100
+		metrics = append(metrics, newMetric("latest_release_age_days", ageDays))
+	}
+
+	commits, err := s.client.CountCommitsSince(ctx, repo, since)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to count commits: %w", err))
+	} else {
+		metrics = append(metrics, newMetric("commits_count", float64(commits), "window", window))
+	}
+
+	runs, err := s.client.GetCompletedWorkflowRuns(ctx, repo, since, s.workflowMaxPages)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get workflow runs: %w", err))
+	} else {
+		metrics = append(metrics, workflowMetrics(runs, newMetric, window)...)
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+// workflowMetrics counts the completed runs from the total GitHub reports, so
+// the count stays right when not every run was fetched. The success rate
+// covers the fetched runs that succeeded or failed, and
+// workflow_success_rate_runs says how many those were.
+func workflowMetrics(runs client.WorkflowRuns, newMetric func(string, float64, ...string) models.Metric, window string) []models.Metric {
+	finished, succeeded := 0, 0
+	for _, run := range runs.Runs {
+		switch run.Conclusion {
+		case "success":
+			succeeded++
+			finished++
+		case "failure", "timed_out", "startup_failure":
+			finished++
+		}
+	}
+
+	metrics := []models.Metric{
+		newMetric("workflow_runs_count", float64(max(runs.Total, len(runs.Runs))), "window", window),
+	}
+
+	if finished > 0 {
+		rate := math.Round(float64(succeeded)/float64(finished)*10000) / 100
+		metrics = append(metrics,
+			newMetric("workflow_success_rate_percent", rate, "window", window),
+			newMetric("workflow_success_rate_runs", float64(finished), "window", window),
+		)
+	}
+
+	return metrics
+}
+
+func formatWindow(d time.Duration) string {
+	day := 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+
+	return d.String()
+}
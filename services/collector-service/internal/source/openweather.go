@@ -0,0 +1,145 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/client"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+func init() {
+	Register("openweather", newOpenWeatherSource)
+}
+
+type openWeatherParams struct {
+	BaseURL   string                      `mapstructure:"base_url"`
+	Locations []openWeatherLocationParams `mapstructure:"locations"`
+}
+
+type openWeatherLocationParams struct {
+	Name string   `mapstructure:"name"`
+	City string   `mapstructure:"city"`
+	Lat  *float64 `mapstructure:"lat"`
+	Lon  *float64 `mapstructure:"lon"`
+}
+
+type weatherLocation struct {
+	name     string
+	location client.Location
+}
+
+type OpenWeatherSource struct {
+	name      string
+	locations []weatherLocation
+	client    *client.OpenWeatherClient
+}
+
+func newOpenWeatherSource(cfg config.SourceConfig, global *config.Config) (Source, error) {
+	var params openWeatherParams
+	if err := decodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+
+	if len(params.Locations) == 0 {
+		return nil, MissingParamError{Source: cfg.Name, Param: "locations"}
+	}
+
+	locations := make([]weatherLocation, 0, len(params.Locations))
+	for i, lp := range params.Locations {
+		if (lp.Lat == nil) != (lp.Lon == nil) {
+			return nil, InvalidParamError{
+				Source: cfg.Name,
+				Param:  fmt.Sprintf("locations[%d].lat/lon", i),
+				Reason: "lat and lon must be set together",
+			}
+		}
+
+		hasCoords := lp.Lat != nil
+		if lp.City == "" && !hasCoords {
+			return nil, MissingParamError{Source: cfg.Name, Param: fmt.Sprintf("locations[%d].city or locations[%d].lat/lon", i, i)}
+		}
+
+		name := lp.Name
+		switch {
+		case name != "":
+		case lp.City != "":
+			name = lp.City
+		default:
+			name = strconv.FormatFloat(*lp.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(*lp.Lon, 'f', -1, 64)
+		}
+
+		locations = append(locations, weatherLocation{
+			name:     name,
+			location: client.Location{City: lp.City, Lat: lp.Lat, Lon: lp.Lon},
+		})
+	}
+
+	return &OpenWeatherSource{
+		name:      cfg.Name,
+		locations: locations,
+		client:    client.NewOpenWeatherClient(params.BaseURL, global.OpenWeather.APIKey),
+	}, nil
+}
+
+func (s *OpenWeatherSource) Name() string {
+	return s.name
+}
+
+func (s *OpenWeatherSource) CacheKey() (CacheKey, bool) {
+	if len(s.locations) != 1 {
+		return CacheKey{}, false
+	}
+
+	return CacheKey{
+		Source: "OpenWeatherMap",
+		Name:   "temperature_celsius",
+		Labels: map[string]string{"location": s.locations[0].name},
+	}, true
+}
+
+func (s *OpenWeatherSource) Collect(ctx context.Context) ([]models.Metric, error) {
+	var metrics []models.Metric
+	var errs []error
+
+	for _, loc := range s.locations {
+		data, err := s.client.GetCurrentWeather(ctx, loc.location)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("location %s: %w", loc.name, err))
+			continue
+		}
+
+		now := time.Now()
+		labels := map[string]any{"location": loc.name}
+		if loc.location.City != "" {
+			labels["city"] = loc.location.City
+		}
+
+		for _, v := range []struct {
+			name  string
+			value float64
+		}{
+			{"temperature_celsius", data.Main.Temp},
+			{"feels_like_celsius", data.Main.FeelsLike},
+			{"humidity_percent", data.Main.Humidity},
+			{"pressure_hpa", data.Main.Pressure},
+			{"wind_speed_mps", data.Wind.Speed},
+			{"cloudiness_percent", data.Clouds.All},
+		} {
+			metrics = append(metrics, models.Metric{
+				Source:      "OpenWeatherMap",
+				Name:        v.name,
+				Value:       v.value,
+				Labels:      maps.Clone(labels),
+				CollectedAt: now,
+			})
+		}
+	}
+
+	return metrics, errors.Join(errs...)
+}
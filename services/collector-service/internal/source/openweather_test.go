@@ -0,0 +1,32 @@
+package source
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+)
+
+func TestOpenWeatherSourceRejectsHalfSpecifiedCoordinates(t *testing.T) {
+	for _, loc := range []map[string]any{
+		{"lat": -23.5505},
+		{"lon": -46.6333},
+		{"city": "São Paulo", "lat": -23.5505},
+	} {
+		cfg := config.SourceConfig{
+			Type:   "openweather",
+			Name:   "weather",
+			Params: map[string]any{"locations": []any{loc}},
+		}
+
+		_, err := New(cfg, &config.Config{})
+
+		var invalid InvalidParamError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("New(%v) error = %v, want InvalidParamError", loc, err)
+		}
+		if invalid.Param != "locations[0].lat/lon" {
+			t.Fatalf("New(%v) rejected param %q, want locations[0].lat/lon", loc, invalid.Param)
+		}
+	}
+}
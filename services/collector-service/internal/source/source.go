@@ -0,0 +1,107 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+	"github.com/go-viper/mapstructure/v2"
+)
+
+type Source interface {
+	Name() string
+	Collect(ctx context.Context) ([]models.Metric, error)
+}
+
+// CacheableSource is implemented by sources whose latest value may be served
+// from cache-service instead of calling the upstream API on every poll.
+// CacheKey reports false when the source cannot be served from cache, such
+// as when it collects several targets: cache-service keeps only the latest
+// metric per source and name.
+type CacheableSource interface {
+	Source
+	CacheKey() (CacheKey, bool)
+}
+
+// CacheKey selects the cached metric that stands in for a poll. The cached
+// metric must carry Labels, so one source is not served another's metric.
+type CacheKey struct {
+	Source string
+	Name   string
+	Labels map[string]string
+}
+
+type Factory func(cfg config.SourceConfig, global *config.Config) (Source, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+func Register(sourceType string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[sourceType]; exists {
+		panic(fmt.Sprintf("source type %q is already registered", sourceType))
+	}
+
+	registry[sourceType] = factory
+}
+
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+func New(cfg config.SourceConfig, global *config.Config) (Source, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Type]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, UnknownTypeError{Type: cfg.Type}
+	}
+
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type
+	}
+
+	src, err := factory(cfg, global)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source %q: %w", cfg.Name, err)
+	}
+
+	return src, nil
+}
+
+func decodeParams(params map[string]any, out any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		WeaklyTypedInput: true,
+		Result:           out,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create params decoder: %w", err)
+	}
+
+	if err := decoder.Decode(params); err != nil {
+		return fmt.Errorf("failed to decode params: %w", err)
+	}
+
+	return nil
+}
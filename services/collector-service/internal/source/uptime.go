@@ -0,0 +1,214 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/client"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+func init() {
+	Register("uptime", newUptimeSource)
+}
+
+const defaultUptimeTimeout = 5 * time.Second
+
+type uptimeParams struct {
+	Timeout time.Duration        `mapstructure:"timeout"`
+	Targets []uptimeTargetParams `mapstructure:"targets"`
+}
+
+type uptimeTargetParams struct {
+	Name               string            `mapstructure:"name"`
+	URL                string            `mapstructure:"url"`
+	Method             string            `mapstructure:"method"`
+	ExpectedStatus     []int             `mapstructure:"expected_status"`
+	BodyRegex          string            `mapstructure:"body_regex"`
+	Headers            map[string]string `mapstructure:"headers"`
+	Timeout            time.Duration     `mapstructure:"timeout"`
+	FollowRedirects    *bool             `mapstructure:"follow_redirects"`
+	InsecureSkipVerify bool              `mapstructure:"insecure_skip_verify"`
+}
+
+type UptimeSource struct {
+	name    string
+	targets []client.ProbeTarget
+	prober  *client.HTTPProber
+}
+
+func newUptimeSource(cfg config.SourceConfig, _ *config.Config) (Source, error) {
+	params := uptimeParams{Timeout: defaultUptimeTimeout}
+	if err := decodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+
+	if params.Timeout <= 0 {
+		params.Timeout = defaultUptimeTimeout
+	}
+
+	if len(params.Targets) == 0 {
+		return nil, MissingParamError{Source: cfg.Name, Param: "targets"}
+	}
+
+	targets := make([]client.ProbeTarget, 0, len(params.Targets))
+	for i, tp := range params.Targets {
+		if tp.URL == "" {
+			return nil, MissingParamError{Source: cfg.Name, Param: fmt.Sprintf("targets[%d].url", i)}
+		}
+
+		u, err := url.Parse(tp.URL)
+		if err != nil {
+			return nil, fmt.Errorf("targets[%d]: invalid url '%s': %w", i, tp.URL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, InvalidParamError{
+				Source: cfg.Name,
+				Param:  fmt.Sprintf("targets[%d].url", i),
+				Reason: fmt.Sprintf("'%s' is not an absolute http or https URL", tp.URL),
+			}
+		}
+
+		target := client.ProbeTarget{
+			Name:               tp.Name,
+			URL:                tp.URL,
+			Method:             strings.ToUpper(tp.Method),
+			ExpectedStatus:     tp.ExpectedStatus,
+			Headers:            tp.Headers,
+			Timeout:            tp.Timeout,
+			FollowRedirects:    tp.FollowRedirects == nil || *tp.FollowRedirects,
+			InsecureSkipVerify: tp.InsecureSkipVerify,
+		}
+
+		if target.Name == "" {
+			target.Name = u.Host
+		}
+
+		if target.Timeout <= 0 {
+			target.Timeout = params.Timeout
+		}
+
+		if tp.BodyRegex != "" {
+			target.BodyRegex, err = regexp.Compile(tp.BodyRegex)
+			if err != nil {
+				return nil, fmt.Errorf("targets[%d]: invalid body_regex: %w", i, err)
+			}
+		}
+
+		targets = append(targets, target)
+	}
+
+	return &UptimeSource{
+		name:    cfg.Name,
+		targets: targets,
+		prober:  client.NewHTTPProber(),
+	}, nil
+}
+
+func (s *UptimeSource) Name() string {
+	return s.name
+}
+
+func (s *UptimeSource) CacheKey() (CacheKey, bool) {
+	if len(s.targets) != 1 {
+		return CacheKey{}, false
+	}
+
+	return CacheKey{
+		Source: "UptimeChecker",
+		Name:   "availability_percent",
+		Labels: map[string]string{"target": s.targets[0].Name},
+	}, true
+}
+
+func (s *UptimeSource) Collect(ctx context.Context) ([]models.Metric, error) {
+	results := make([][]models.Metric, len(s.targets))
+	errs := make([]error, len(s.targets))
+
+	var wg sync.WaitGroup
+	for i, target := range s.targets {
+		wg.Add(1)
+		go func(i int, target client.ProbeTarget) {
+			defer wg.Done()
+			results[i], errs[i] = s.probe(ctx, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	var metrics []models.Metric
+	for _, r := range results {
+		metrics = append(metrics, r...)
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+func (s *UptimeSource) probe(ctx context.Context, target client.ProbeTarget) ([]models.Metric, error) {
+	now := time.Now()
+
+	newMetric := func(name string, value float64) models.Metric {
+		return models.Metric{
+			Source: "UptimeChecker",
+			Name:   name,
+			Value:  value,
+			Labels: map[string]any{
+				"target": target.Name,
+				"url":    target.URL,
+			},
+			CollectedAt: now,
+		}
+	}
+
+	result, err := s.prober.Probe(ctx, target)
+	if result == nil {
+		return []models.Metric{newMetric("availability_percent", 0)}, fmt.Errorf("target %s: %w", target.Name, err)
+	}
+
+	availability := 0.0
+	if result.Up {
+		availability = 100
+	}
+
+	metrics := []models.Metric{
+		newMetric("availability_percent", availability),
+		newMetric("response_time_seconds", result.Duration.Seconds()),
+	}
+
+	if result.StatusCode != 0 {
+		metrics = append(metrics, newMetric("http_status_code", float64(result.StatusCode)))
+	}
+	if result.DNSLookup > 0 {
+		metrics = append(metrics, newMetric("dns_lookup_seconds", result.DNSLookup.Seconds()))
+	}
+	if result.Connect > 0 {
+		metrics = append(metrics, newMetric("connect_seconds", result.Connect.Seconds()))
+	}
+	if result.TLSHandshake > 0 {
+		metrics = append(metrics, newMetric("tls_handshake_seconds", result.TLSHandshake.Seconds()))
+	}
+	if result.TTFB > 0 {
+		metrics = append(metrics, newMetric("ttfb_seconds", result.TTFB.Seconds()))
+	}
+	if !result.CertExpiry.IsZero() {
+		days := math.Floor(time.Until(result.CertExpiry).Hours()/24*100) / 100
+		metrics = append(metrics, newMetric("tls_cert_expiry_days", days))
+	}
+
+	if err != nil {
+		return metrics, fmt.Errorf("target %s: %w", target.Name, err)
+	}
+
+	if !result.Up {
+		return metrics, fmt.Errorf("target %s is down: %s", target.Name, result.Reason)
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,170 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+const (
+	defaultRetryInterval = 5 * time.Second
+	defaultMaxInFlight   = 64
+	replaySendTimeout    = 30 * time.Second
+)
+
+// Broker wraps another broker and spools batches to disk when it fails or
+// when maxInFlight sends to it are already under way. While anything is
+// spooled, new batches are appended behind it as well so they are replayed in
+// order once the wrapped broker recovers or catches up.
+type Broker struct {
+	inner         broker.MessageBroker
+	spool         *Spool
+	retryInterval time.Duration
+	inFlight      chan struct{}
+	log           logger.Logger
+	metrics       *metrics.Metrics
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewBroker(inner broker.MessageBroker, cfg config.SpoolConfig, log logger.Logger, m *metrics.Metrics) (*Broker, error) {
+	spool, err := Open(cfg, log, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool: %w", err)
+	}
+
+	retryInterval := cfg.RetryInterval
+	if retryInterval <= 0 {
+		retryInterval = defaultRetryInterval
+	}
+
+	maxInFlight := cfg.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = defaultMaxInFlight
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Broker{
+		inner:         inner,
+		spool:         spool,
+		retryInterval: retryInterval,
+		inFlight:      make(chan struct{}, maxInFlight),
+		log:           log,
+		metrics:       m,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+
+	go b.replayLoop(ctx)
+
+	return b, nil
+}
+
+func (b *Broker) SendMetrics(ctx context.Context, metrics []models.Metric) error {
+	if b.spool.Len() == 0 {
+		sent, err := b.send(ctx, metrics)
+		if sent {
+			return err
+		}
+	}
+
+	if err := b.spool.Append(metrics); err != nil {
+		return fmt.Errorf("failed to spool %d metrics: %w", len(metrics), err)
+	}
+
+	return nil
+}
+
+// send passes the batch to the wrapped broker unless maxInFlight sends are
+// under way. It reports false if the batch still has to be spooled.
+func (b *Broker) send(ctx context.Context, metrics []models.Metric) (bool, error) {
+	select {
+	case b.inFlight <- struct{}{}:
+	default:
+		b.log.Debug("broker busy, spooling batch", "count", len(metrics))
+		b.metrics.SpoolBatches.WithLabelValues("overflowed").Inc()
+		return false, nil
+	}
+
+	err := b.inner.SendMetrics(ctx, metrics)
+	<-b.inFlight
+
+	var serErr *broker.SerialisationError
+	if err == nil || errors.As(err, &serErr) {
+		return true, err
+	}
+
+	b.log.Warn("broker unavailable, spooling batch", "count", len(metrics), "error", err)
+	return false, nil
+}
+
+func (b *Broker) Close() error {
+	b.cancel()
+	<-b.done
+
+	return errors.Join(b.spool.Close(), b.inner.Close())
+}
+
+func (b *Broker) replayLoop(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.replay(ctx)
+		}
+	}
+}
+
+// replay sends spooled batches until the spool is empty or a send fails.
+func (b *Broker) replay(ctx context.Context) {
+	replayed := 0
+
+	for ctx.Err() == nil {
+		batch, ok, err := b.spool.Peek()
+		if err != nil {
+			b.log.Error("failed to read spool", "error", err)
+			return
+		}
+		if !ok {
+			break
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, replaySendTimeout)
+		err = b.inner.SendMetrics(sendCtx, batch)
+		cancel()
+
+		var serErr *broker.SerialisationError
+		if err != nil && !errors.As(err, &serErr) {
+			b.log.Warn("failed to replay spooled batch, will retry", "error", err)
+			return
+		}
+		if serErr != nil {
+			// Retrying cannot fix metrics that do not serialise.
+			b.log.Warn("some spooled metrics failed to be serialised", "error", serErr)
+		}
+
+		if err := b.spool.Ack(); err != nil {
+			b.log.Error("failed to acknowledge spooled batch", "error", err)
+			return
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		b.log.Info("replayed spooled batches", "count", replayed)
+	}
+}
@@ -0,0 +1,23 @@
+package spool
+
+import "fmt"
+
+type MissingDirError struct{}
+
+func (e MissingDirError) Error() string {
+	return "spool directory is not configured"
+}
+
+type FullError struct {
+	MaxBytes int64
+}
+
+func (e FullError) Error() string {
+	return fmt.Sprintf("spool is full: batch does not fit in %d bytes", e.MaxBytes)
+}
+
+type ClosedError struct{}
+
+func (e ClosedError) Error() string {
+	return "spool is closed"
+}
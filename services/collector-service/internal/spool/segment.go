@@ -0,0 +1,136 @@
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Records are stored as a little-endian uint32 payload length, a CRC-32C of
+// the payload and the payload itself.
+const (
+	headerSize       = 8
+	segmentExtension = ".seg"
+	maxRecordBytes   = 256 << 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errCorruptRecord = errors.New("corrupt spool record")
+
+type segment struct {
+	id   uint64
+	path string
+	// size is the number of bytes on disk; end is where readable records
+	// stop, which is lower than size when the tail is corrupt.
+	size    int64
+	end     int64
+	records int
+	modTime time.Time
+}
+
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", id, segmentExtension))
+}
+
+func parseSegmentID(name string) (uint64, bool) {
+	if !strings.HasSuffix(name, segmentExtension) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExtension), 10, 64)
+	return id, err == nil
+}
+
+func encodeRecord(payload []byte) []byte {
+	record := make([]byte, headerSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(payload, crcTable))
+	copy(record[headerSize:], payload)
+	return record
+}
+
+// readRecord reads the record at offset and returns its payload and the
+// total number of bytes it occupies.
+func readRecord(f *os.File, offset int64) ([]byte, int64, error) {
+	var header [headerSize]byte
+	if _, err := f.ReadAt(header[:], offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, 0, errCorruptRecord
+		}
+		return nil, 0, err
+	}
+
+	length := binary.LittleEndian.Uint32(header[0:4])
+	if length == 0 || length > maxRecordBytes {
+		return nil, 0, errCorruptRecord
+	}
+
+	payload := make([]byte, length)
+	if _, err := f.ReadAt(payload, offset+headerSize); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, 0, errCorruptRecord
+		}
+		return nil, 0, err
+	}
+
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:8]) {
+		return nil, 0, errCorruptRecord
+	}
+
+	return payload, headerSize + int64(length), nil
+}
+
+// scanSegment walks the records from offset and stops at the first corrupt or
+// truncated one.
+func scanSegment(seg *segment, offset int64) error {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	seg.size = info.Size()
+	seg.modTime = info.ModTime()
+
+	r := bufio.NewReader(io.NewSectionReader(f, offset, seg.size-offset))
+	pos := offset
+	var header [headerSize]byte
+
+	for pos < seg.size {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			break
+		}
+
+		length := binary.LittleEndian.Uint32(header[0:4])
+		if length == 0 || length > maxRecordBytes || pos+headerSize+int64(length) > seg.size {
+			break
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			break
+		}
+		if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:8]) {
+			break
+		}
+
+		pos += headerSize + int64(length)
+		seg.records++
+	}
+
+	seg.end = pos
+	return nil
+}
@@ -0,0 +1,476 @@
+package spool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+const (
+	defaultSegmentBytes = 16 << 20
+	defaultMaxBytes     = 1 << 30
+	defaultMaxAge       = 24 * time.Hour
+	cursorFile          = "cursor"
+)
+
+// Spool is a segment-based write-ahead log of metric batches. Batches are
+// appended to the newest segment and read back in order from the oldest one;
+// the read position is persisted in a cursor file so replayed batches are not
+// sent again after a restart. Fully replayed segments are deleted.
+type Spool struct {
+	dir          string
+	segmentBytes int64
+	maxBytes     int64
+	maxAge       time.Duration
+	log          logger.Logger
+	metrics      *metrics.Metrics
+
+	mu       sync.Mutex
+	segments []*segment
+	active   *os.File
+	reader   *os.File
+	offset   int64
+	pending  int64
+	closed   bool
+}
+
+func Open(cfg config.SpoolConfig, log logger.Logger, m *metrics.Metrics) (*Spool, error) {
+	s := &Spool{
+		dir:          cfg.Dir,
+		segmentBytes: cfg.SegmentBytes,
+		maxBytes:     cfg.MaxBytes,
+		maxAge:       cfg.MaxAge,
+		log:          log,
+		metrics:      m,
+	}
+
+	if s.dir == "" {
+		return nil, MissingDirError{}
+	}
+	if s.segmentBytes <= 0 {
+		s.segmentBytes = defaultSegmentBytes
+	}
+	if s.maxBytes <= 0 {
+		s.maxBytes = defaultMaxBytes
+	}
+	if s.maxAge <= 0 {
+		s.maxAge = defaultMaxAge
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+
+	s.updateGauges()
+	return s, nil
+}
+
+// recover loads the segments left by a previous run. Records after the first
+// corrupt one in a segment are unreadable and skipped; a torn write at the end
+// of the newest segment is truncated so appends can continue.
+func (s *Spool) recover() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	var ids []uint64
+	for _, e := range entries {
+		if id, ok := parseSegmentID(e.Name()); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	cursorID, cursorOffset := s.readCursor()
+
+	for _, id := range ids {
+		path := segmentPath(s.dir, id)
+		if id < cursorID {
+			// Already replayed but not yet deleted when the process stopped.
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove replayed segment: %w", err)
+			}
+			continue
+		}
+
+		seg := &segment{id: id, path: path}
+		offset := int64(0)
+		if id == cursorID {
+			offset = cursorOffset
+		}
+
+		if err := scanSegment(seg, offset); err != nil {
+			return fmt.Errorf("failed to scan spool segment %d: %w", id, err)
+		}
+		if offset > seg.size {
+			s.log.Warn("spool cursor is beyond segment end, replaying segment from start", "segment", id)
+			offset = 0
+			seg.records = 0
+			if err := scanSegment(seg, 0); err != nil {
+				return fmt.Errorf("failed to scan spool segment %d: %w", id, err)
+			}
+		}
+
+		if seg.end < seg.size {
+			s.log.Warn("corrupt records found in spool segment", "segment", id, "valid_bytes", seg.end, "size", seg.size)
+			s.metrics.SpoolBatches.WithLabelValues("dropped_corrupt").Inc()
+		}
+
+		if len(s.segments) == 0 {
+			s.offset = offset
+		}
+		s.segments = append(s.segments, seg)
+	}
+
+	if len(s.segments) == 0 {
+		return s.createSegment(cursorID + 1)
+	}
+
+	last := s.segments[len(s.segments)-1]
+	if last.end < last.size {
+		if err := os.Truncate(last.path, last.end); err != nil {
+			return fmt.Errorf("failed to truncate spool segment %d: %w", last.id, err)
+		}
+		last.size = last.end
+	}
+
+	active, err := os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open spool segment %d: %w", last.id, err)
+	}
+	s.active = active
+
+	if depth := s.depth(); depth > 0 {
+		s.log.Info("recovered spooled batches", "batches", depth, "segments", len(s.segments))
+	}
+
+	return nil
+}
+
+// Len returns the number of batches waiting to be replayed.
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.depth()
+}
+
+func (s *Spool) Append(batch []models.Metric) error {
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+	record := encodeRecord(payload)
+	size := int64(len(record))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ClosedError{}
+	}
+
+	active := s.segments[len(s.segments)-1]
+	if active.size > 0 && active.size+size > s.segmentBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+		active = s.segments[len(s.segments)-1]
+	}
+
+	for s.totalBytes()+size > s.maxBytes && len(s.segments) > 1 {
+		if err := s.dropOldest("size"); err != nil {
+			return err
+		}
+	}
+	if s.totalBytes()+size > s.maxBytes {
+		return FullError{MaxBytes: s.maxBytes}
+	}
+
+	if _, err := s.active.Write(record); err != nil {
+		return fmt.Errorf("failed to write spool record: %w", err)
+	}
+	if err := s.active.Sync(); err != nil {
+		return fmt.Errorf("failed to sync spool segment: %w", err)
+	}
+
+	active.size += size
+	active.end = active.size
+	active.records++
+	active.modTime = time.Now()
+
+	s.metrics.SpoolBatches.WithLabelValues("spooled").Inc()
+	s.updateGauges()
+
+	return nil
+}
+
+// Peek returns the oldest spooled batch without removing it. ok is false when
+// the spool is empty. Call Ack once the batch has been delivered.
+func (s *Spool) Peek() (batch []models.Metric, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, false, ClosedError{}
+	}
+
+	if err := s.expire(); err != nil {
+		return nil, false, err
+	}
+
+	for {
+		seg := s.segments[0]
+
+		if s.offset >= seg.end {
+			if len(s.segments) == 1 {
+				return nil, false, nil
+			}
+			if err := s.removeFirst(); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		if s.reader == nil {
+			if s.reader, err = os.Open(seg.path); err != nil {
+				return nil, false, fmt.Errorf("failed to open spool segment %d: %w", seg.id, err)
+			}
+		}
+
+		payload, n, err := readRecord(s.reader, s.offset)
+		if errors.Is(err, errCorruptRecord) {
+			s.log.Warn("skipping corrupt spool records", "segment", seg.id, "offset", s.offset)
+			s.metrics.SpoolBatches.WithLabelValues("dropped_corrupt").Inc()
+			seg.end = s.offset
+			seg.records = 0
+			if len(s.segments) == 1 {
+				// New records must not land behind the corrupt region.
+				if err := s.rotate(); err != nil {
+					return nil, false, err
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read spool segment %d: %w", seg.id, err)
+		}
+
+		if err := json.Unmarshal(payload, &batch); err != nil {
+			s.log.Warn("skipping undecodable spool record", "segment", seg.id, "offset", s.offset, "error", err)
+			s.metrics.SpoolBatches.WithLabelValues("dropped_corrupt").Inc()
+			s.offset += n
+			seg.records--
+			continue
+		}
+
+		s.pending = n
+		return batch, true, nil
+	}
+}
+
+// Ack removes the batch returned by the last Peek.
+func (s *Spool) Ack() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ClosedError{}
+	}
+	if s.pending == 0 {
+		return nil
+	}
+
+	seg := s.segments[0]
+	s.offset += s.pending
+	s.pending = 0
+	seg.records--
+
+	s.metrics.SpoolBatches.WithLabelValues("replayed").Inc()
+
+	if s.offset >= seg.end && len(s.segments) > 1 {
+		if err := s.removeFirst(); err != nil {
+			return err
+		}
+	} else if err := s.writeCursor(); err != nil {
+		return err
+	}
+
+	s.updateGauges()
+	return nil
+}
+
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	if s.reader != nil {
+		errs = append(errs, s.reader.Close())
+		s.reader = nil
+	}
+	if s.active != nil {
+		errs = append(errs, s.active.Close())
+		s.active = nil
+	}
+	errs = append(errs, s.writeCursor())
+
+	return errors.Join(errs...)
+}
+
+// expire drops segments whose newest record is older than maxAge.
+func (s *Spool) expire() error {
+	now := time.Now()
+
+	for now.Sub(s.segments[0].modTime) > s.maxAge && s.depth() > 0 {
+		if len(s.segments) == 1 {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+		if err := s.dropOldest("age"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Spool) dropOldest(reason string) error {
+	seg := s.segments[0]
+	s.log.Warn("dropping spool segment", "segment", seg.id, "reason", reason, "batches", seg.records)
+	s.metrics.SpoolBatches.WithLabelValues("dropped_" + reason).Add(float64(seg.records))
+
+	return s.removeFirst()
+}
+
+// removeFirst deletes the oldest segment and moves the cursor to the next one.
+// The newest segment is never removed.
+func (s *Spool) removeFirst() error {
+	seg := s.segments[0]
+
+	if s.reader != nil {
+		s.reader.Close()
+		s.reader = nil
+	}
+
+	if err := os.Remove(seg.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spool segment %d: %w", seg.id, err)
+	}
+
+	s.segments = s.segments[1:]
+	s.offset = 0
+	s.pending = 0
+	s.updateGauges()
+
+	return s.writeCursor()
+}
+
+func (s *Spool) rotate() error {
+	if err := s.active.Close(); err != nil {
+		return fmt.Errorf("failed to close spool segment: %w", err)
+	}
+
+	return s.createSegment(s.segments[len(s.segments)-1].id + 1)
+}
+
+func (s *Spool) createSegment(id uint64) error {
+	path := segmentPath(s.dir, id)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create spool segment %d: %w", id, err)
+	}
+
+	s.active = f
+	s.segments = append(s.segments, &segment{id: id, path: path, modTime: time.Now()})
+	s.updateGauges()
+
+	return nil
+}
+
+func (s *Spool) readCursor() (uint64, int64) {
+	data, err := os.ReadFile(filepath.Join(s.dir, cursorFile))
+	if err != nil {
+		return 0, 0
+	}
+
+	idStr, offsetStr, ok := strings.Cut(strings.TrimSpace(string(data)), " ")
+	if !ok {
+		s.log.Warn("ignoring malformed spool cursor")
+		return 0, 0
+	}
+
+	id, idErr := strconv.ParseUint(idStr, 10, 64)
+	offset, offsetErr := strconv.ParseInt(offsetStr, 10, 64)
+	if idErr != nil || offsetErr != nil || offset < 0 {
+		s.log.Warn("ignoring malformed spool cursor")
+		return 0, 0
+	}
+
+	return id, offset
+}
+
+// writeCursor persists the read position atomically via a rename.
+func (s *Spool) writeCursor() error {
+	if len(s.segments) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(s.dir, cursorFile)
+	tmp := path + ".tmp"
+	data := fmt.Sprintf("%d %d\n", s.segments[0].id, s.offset)
+
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write spool cursor: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write spool cursor: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Spool) depth() int {
+	depth := 0
+	for _, seg := range s.segments {
+		depth += seg.records
+	}
+	return depth
+}
+
+func (s *Spool) totalBytes() int64 {
+	var total int64
+	for _, seg := range s.segments {
+		total += seg.size
+	}
+	return total
+}
+
+func (s *Spool) updateGauges() {
+	s.metrics.SpoolDepthBatches.Set(float64(s.depth()))
+	s.metrics.SpoolDepthBytes.Set(float64(s.totalBytes()))
+	s.metrics.SpoolSegments.Set(float64(len(s.segments)))
+}
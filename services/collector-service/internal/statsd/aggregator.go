@@ -0,0 +1,246 @@
+package statsd
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+const (
+	defaultMaxTimerSamples = 10000
+	// gaugeTTL bounds how long an idle gauge is remembered for relative updates.
+	gaugeTTL = time.Hour
+)
+
+var percentiles = []struct {
+	suffix string
+	value  float64
+}{
+	{"_p50", 0.50},
+	{"_p90", 0.90},
+	{"_p99", 0.99},
+}
+
+type series struct {
+	source string
+	name   string
+	labels map[string]any
+}
+
+type counterState struct {
+	series
+	count float64
+}
+
+type gaugeState struct {
+	series
+	value     float64
+	updated   bool
+	updatedAt time.Time
+}
+
+type timerState struct {
+	series
+	count   float64
+	seen    int
+	sum     float64
+	min     float64
+	max     float64
+	samples []float64
+}
+
+type setState struct {
+	series
+	members map[string]struct{}
+}
+
+// Aggregator accumulates StatsD samples between flushes. Counters, timers and
+// sets are reset on every flush; gauges keep their value so relative updates
+// apply to the last known value, but are only reported when updated.
+type Aggregator struct {
+	maxTimerSamples int
+
+	mu       sync.Mutex
+	counters map[string]*counterState
+	gauges   map[string]*gaugeState
+	timers   map[string]*timerState
+	sets     map[string]*setState
+}
+
+func NewAggregator(maxTimerSamples int) *Aggregator {
+	if maxTimerSamples <= 0 {
+		maxTimerSamples = defaultMaxTimerSamples
+	}
+
+	return &Aggregator{
+		maxTimerSamples: maxTimerSamples,
+		counters:        make(map[string]*counterState),
+		gauges:          make(map[string]*gaugeState),
+		timers:          make(map[string]*timerState),
+		sets:            make(map[string]*setState),
+	}
+}
+
+func (a *Aggregator) Add(s Sample) {
+	key := seriesKey(s)
+	sr := series{source: s.Source, name: s.Name, labels: s.Labels}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch s.Type {
+	case Counter:
+		c, ok := a.counters[key]
+		if !ok {
+			c = &counterState{series: sr}
+			a.counters[key] = c
+		}
+		c.count += s.Value / s.SampleRate
+
+	case Gauge:
+		g, ok := a.gauges[key]
+		if !ok {
+			g = &gaugeState{series: sr}
+			a.gauges[key] = g
+		}
+		if s.Relative {
+			g.value += s.Value
+		} else {
+			g.value = s.Value
+		}
+		g.updated = true
+		g.updatedAt = time.Now()
+
+	case Timer:
+		t, ok := a.timers[key]
+		if !ok {
+			t = &timerState{series: sr, min: s.Value, max: s.Value}
+			a.timers[key] = t
+		}
+		t.count += 1 / s.SampleRate
+		t.sum += s.Value / s.SampleRate
+		t.min = math.Min(t.min, s.Value)
+		t.max = math.Max(t.max, s.Value)
+
+		// Reservoir sampling keeps percentiles representative without
+		// holding every sample of a busy timer.
+		t.seen++
+		if len(t.samples) < a.maxTimerSamples {
+			t.samples = append(t.samples, s.Value)
+		} else if j := rand.IntN(t.seen); j < a.maxTimerSamples {
+			t.samples[j] = s.Value
+		}
+
+	case Set:
+		st, ok := a.sets[key]
+		if !ok {
+			st = &setState{series: sr, members: make(map[string]struct{})}
+			a.sets[key] = st
+		}
+		st.members[s.SetValue] = struct{}{}
+	}
+}
+
+// Flush returns the aggregates for the elapsed interval and resets the state.
+// Rates are per second over interval.
+func (a *Aggregator) Flush(now time.Time, interval time.Duration) []models.Metric {
+	a.mu.Lock()
+	counters, timers, sets := a.counters, a.timers, a.sets
+	a.counters = make(map[string]*counterState)
+	a.timers = make(map[string]*timerState)
+	a.sets = make(map[string]*setState)
+
+	var gauges []gaugeState
+	for key, g := range a.gauges {
+		switch {
+		case g.updated:
+			gauges = append(gauges, *g)
+			g.updated = false
+		case now.Sub(g.updatedAt) > gaugeTTL:
+			delete(a.gauges, key)
+		}
+	}
+	a.mu.Unlock()
+
+	seconds := interval.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	var metrics []models.Metric
+	emit := func(sr series, suffix string, value float64) {
+		metrics = append(metrics, models.Metric{
+			Source:      sr.source,
+			Name:        sr.name + suffix,
+			Value:       value,
+			Labels:      sr.labels,
+			CollectedAt: now,
+		})
+	}
+
+	for _, c := range counters {
+		emit(c.series, "_count", c.count)
+		emit(c.series, "_rate", c.count/seconds)
+	}
+
+	for _, g := range gauges {
+		emit(g.series, "", g.value)
+	}
+
+	for _, t := range timers {
+		emit(t.series, "_count", t.count)
+		emit(t.series, "_rate", t.count/seconds)
+		emit(t.series, "_sum", t.sum)
+		emit(t.series, "_mean", t.sum/t.count)
+		emit(t.series, "_min", t.min)
+		emit(t.series, "_max", t.max)
+
+		slices.Sort(t.samples)
+		for _, p := range percentiles {
+			emit(t.series, p.suffix, percentile(t.samples, p.value))
+		}
+	}
+
+	for _, st := range sets {
+		emit(st.series, "_unique", float64(len(st.members)))
+	}
+
+	return metrics
+}
+
+// percentile uses the nearest-rank method on sorted samples.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(idx, 0)]
+}
+
+func seriesKey(s Sample) string {
+	var sb strings.Builder
+	sb.WriteString(s.Source)
+	sb.WriteByte('.')
+	sb.WriteString(s.Name)
+
+	keys := make([]string, 0, len(s.Labels))
+	for k := range s.Labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		sb.WriteByte(0)
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(s.Labels[k].(string))
+	}
+
+	return sb.String()
+}
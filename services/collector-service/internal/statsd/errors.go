@@ -0,0 +1,11 @@
+package statsd
+
+import "fmt"
+
+type ParseError struct {
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("invalid statsd line: %s", e.Reason)
+}
@@ -0,0 +1,119 @@
+package statsd
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+)
+
+type MetricType string
+
+const (
+	Counter MetricType = "c"
+	Gauge   MetricType = "g"
+	Timer   MetricType = "ms"
+	Set     MetricType = "s"
+)
+
+// Sample is a single parsed StatsD line.
+type Sample struct {
+	Source string
+	Name   string
+	Type   MetricType
+	Value  float64
+	// SetValue holds the raw member for sets, which are not numeric.
+	SetValue string
+	// Relative marks gauge updates written as +N or -N.
+	Relative   bool
+	SampleRate float64
+	Labels     map[string]any
+}
+
+// Parse reads a StatsD line with optional DogStatsD extensions:
+//
+//	name:value|type[|@sample_rate][|#tag:value,tag...]
+//
+// Histograms and distributions are treated as timers. As with Graphite, the
+// first segment of the dotted name becomes the metric source.
+func Parse(line []byte, defaultSource string) (Sample, error) {
+	s := string(line)
+	if strings.HasPrefix(s, "_e{") || strings.HasPrefix(s, "_sc|") {
+		return Sample{}, ParseError{Reason: "events and service checks are not supported"}
+	}
+
+	path, rest, ok := strings.Cut(s, ":")
+	if !ok || path == "" {
+		return Sample{}, ParseError{Reason: "expected 'name:value|type'"}
+	}
+
+	sections := strings.Split(rest, "|")
+	if len(sections) < 2 {
+		return Sample{}, ParseError{Reason: "missing metric type"}
+	}
+
+	sample := Sample{SampleRate: 1, Labels: make(map[string]any)}
+
+	switch sections[1] {
+	case "c":
+		sample.Type = Counter
+	case "g":
+		sample.Type = Gauge
+	case "ms", "h", "d":
+		sample.Type = Timer
+	case "s":
+		sample.Type = Set
+	default:
+		return Sample{}, ParseError{Reason: "unsupported metric type '" + sections[1] + "'"}
+	}
+
+	raw := sections[0]
+	if sample.Type == Set {
+		if raw == "" {
+			return Sample{}, ParseError{Reason: "missing set value"}
+		}
+		sample.SetValue = raw
+	} else {
+		value, err := strconv.ParseFloat(raw, 64)
+		// ParseFloat accepts NaN and Inf, which would poison the aggregates
+		// of the whole flush interval.
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return Sample{}, ParseError{Reason: "invalid value '" + raw + "'"}
+		}
+		sample.Value = value
+		sample.Relative = sample.Type == Gauge && (raw[0] == '+' || raw[0] == '-')
+	}
+
+	for _, section := range sections[2:] {
+		switch {
+		case strings.HasPrefix(section, "@"):
+			rate, err := strconv.ParseFloat(section[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return Sample{}, ParseError{Reason: "invalid sample rate '" + section + "'"}
+			}
+			sample.SampleRate = rate
+		case strings.HasPrefix(section, "#"):
+			for tag := range strings.SplitSeq(section[1:], ",") {
+				if tag == "" {
+					continue
+				}
+				key, value, _ := strings.Cut(tag, ":")
+				sample.Labels[ingest.SanitizeLabelKey(key)] = value
+			}
+		}
+		// Other DogStatsD sections such as container IDs and timestamps are ignored.
+	}
+
+	source, name, ok := strings.Cut(path, ".")
+	if !ok {
+		source, name = defaultSource, path
+	}
+	if source == "" || name == "" {
+		return Sample{}, ParseError{Reason: "invalid metric name '" + path + "'"}
+	}
+	sample.Source = source
+	sample.Name = name
+
+	return sample, nil
+}
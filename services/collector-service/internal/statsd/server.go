@@ -0,0 +1,159 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/ingest"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/listener"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+const (
+	defaultFlushInterval = 10 * time.Second
+	defaultSource        = "statsd"
+)
+
+// Server receives StatsD packets over UDP and publishes the aggregates once
+// per flush interval instead of forwarding every packet.
+type Server struct {
+	udp           *listener.UDPListener
+	aggregator    *Aggregator
+	validator     *ingest.Validator
+	broker        broker.MessageBroker
+	flushInterval time.Duration
+	defaultSource string
+	log           logger.Logger
+	metrics       *metrics.Metrics
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	// started is set by Serve; flushed is closed only once the flush loop
+	// it starts has returned.
+	started atomic.Bool
+	flushed chan struct{}
+}
+
+func NewServer(addr string, flushInterval time.Duration, defaultSrc string, maxTimerSamples int, validator *ingest.Validator, broker broker.MessageBroker, log logger.Logger, m *metrics.Metrics) *Server {
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	if defaultSrc == "" {
+		defaultSrc = defaultSource
+	}
+
+	s := &Server{
+		aggregator:    NewAggregator(maxTimerSamples),
+		validator:     validator,
+		broker:        broker,
+		flushInterval: flushInterval,
+		defaultSource: defaultSrc,
+		log:           log,
+		metrics:       m,
+		stop:          make(chan struct{}),
+		flushed:       make(chan struct{}),
+	}
+	s.udp = listener.NewUDP("statsd", addr, s.handleLines, log, m)
+
+	return s
+}
+
+func (s *Server) Name() string {
+	return "statsd"
+}
+
+func (s *Server) Serve() error {
+	s.started.Store(true)
+	go s.flushLoop()
+
+	err := s.udp.Serve()
+	if err != nil {
+		s.stopOnce.Do(func() { close(s.stop) })
+	}
+
+	return err
+}
+
+// Shutdown stops receiving packets and publishes what has been aggregated so far.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.udp.Shutdown(ctx)
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	if !s.started.Load() {
+		return err
+	}
+
+	select {
+	case <-s.flushed:
+	case <-ctx.Done():
+		return errors.Join(err, ctx.Err())
+	}
+
+	return err
+}
+
+func (s *Server) handleLines(_ context.Context, lines []ingest.Line) {
+	accepted, rejected := 0, 0
+
+	for _, line := range lines {
+		sample, err := Parse(line.Text, s.defaultSource)
+		if err == nil {
+			// Values are aggregated later, so only the identity is validated here.
+			err = s.validator.Validate(&models.Metric{Source: sample.Source, Name: sample.Name, Labels: sample.Labels})
+		}
+		if err != nil {
+			rejected++
+			s.log.Debug("statsd line rejected", "error", err)
+			continue
+		}
+
+		s.aggregator.Add(sample)
+		accepted++
+	}
+
+	s.metrics.IngestedMetrics.WithLabelValues("statsd", "accepted").Add(float64(accepted))
+	s.metrics.IngestedMetrics.WithLabelValues("statsd", "rejected").Add(float64(rejected))
+}
+
+func (s *Server) flushLoop() {
+	defer close(s.flushed)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.flush(now, now.Sub(last))
+			last = now
+		case <-s.stop:
+			now := time.Now()
+			s.flush(now, now.Sub(last))
+			return
+		}
+	}
+}
+
+func (s *Server) flush(now time.Time, interval time.Duration) {
+	batch := s.aggregator.Flush(now, interval)
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.flushInterval)
+	defer cancel()
+
+	if err := s.broker.SendMetrics(ctx, batch); err != nil {
+		s.log.Error("failed to send statsd aggregates", "count", len(batch), "error", err)
+		return
+	}
+
+	s.log.Debug("statsd aggregates flushed", "count", len(batch))
+}
@@ -2,27 +2,28 @@ package worker
 
 import (
 	"context"
-	"net/http"
+	"fmt"
+	"sync"
 	"time"
 
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/client"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/source"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/grpc"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
 )
 
+type job struct {
+	source   source.Source
+	interval time.Duration
+}
+
 type Worker struct {
 	broker       broker.MessageBroker
 	cacheClient  *grpc.CacheClient
 	log          logger.Logger
 	pollInterval time.Duration
-
-	githubClient *client.GithubClient
-	githubRepo   string
-
-	weatherClient *client.OpenWeatherClient
-	weatherCity   string
+	jobs         []job
 }
 
 func New(
@@ -30,192 +31,121 @@ func New(
 	cacheClient *grpc.CacheClient,
 	log logger.Logger,
 	pollInterval time.Duration,
-
-	githubClient *client.GithubClient,
-	githubRepo string,
-
-	weatherClient *client.OpenWeatherClient,
-	weatherCity string,
 ) *Worker {
 	return &Worker{
 		broker:       broker,
 		cacheClient:  cacheClient,
 		log:          log,
 		pollInterval: pollInterval,
+	}
+}
 
-		githubClient: githubClient,
-		githubRepo:   githubRepo,
-
-		weatherClient: weatherClient,
-		weatherCity:   weatherCity,
+func (w *Worker) Schedule(src source.Source, interval time.Duration) {
+	if interval <= 0 {
+		interval = w.pollInterval
 	}
+
+	w.jobs = append(w.jobs, job{source: src, interval: interval})
 }
 
 func (w *Worker) Start(ctx context.Context) {
-	w.log.Info("starting worker", "poll_interval", w.pollInterval)
-	ticker := time.NewTicker(w.pollInterval)
+	w.log.Info("starting worker", "sources", len(w.jobs), "poll_interval", w.pollInterval)
+
+	var wg sync.WaitGroup
+	for _, j := range w.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			w.run(ctx, j)
+		}(j)
+	}
+
+	wg.Wait()
+	w.log.Info("worker stopped")
+}
+
+func (w *Worker) run(ctx context.Context, j job) {
+	w.log.Info("starting source", "source", j.source.Name(), "interval", j.interval)
+	ticker := time.NewTicker(j.interval)
 	defer ticker.Stop()
 
-	w.collectAllMetrics(ctx)
+	w.collect(ctx, j.source)
 
 	for {
 		select {
 		case <-ticker.C:
-			w.collectAllMetrics(ctx)
+			w.collect(ctx, j.source)
 		case <-ctx.Done():
-			w.log.Info("worker stopped")
 			return
 		}
 	}
 }
 
-func (w *Worker) collectAllMetrics(ctx context.Context) {
-	w.collectUptimeMetrics(ctx)
-	w.collectGithubMetrics(ctx)
-	w.collectOpenWeatherMetrics(ctx)
-}
-
-func checkUptime(url string, timeout time.Duration) (float64, error) {
-	client := &http.Client{Timeout: timeout}
-	resp, err := client.Get(url)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return 100, nil
-	}
-
-	return 0, nil
-}
-
-func (w *Worker) collectUptimeMetrics(ctx context.Context) {
-	w.log.Info("collecting uptime metrics...")
-
-	cachedMetric, err := w.cacheClient.GetCachedMetric(ctx, "UptimeChecker", "availability_percent")
-	if err != nil {
-		w.log.Warn("failed to get from cache, falling back to API", "error", err)
-	} else if cachedMetric != nil {
-		w.log.Info("using cached uptimechecher metric", "percent", cachedMetric.Value)
-		metric := *cachedMetric
-
-		metric.CollectedAt = time.Now()
-		if metric.Labels == nil {
-			metric.Labels = map[string]any{}
-		}
-		metric.Labels["cached"] = "true"
+func (w *Worker) collect(ctx context.Context, src source.Source) {
+	w.log.Info("collecting metrics...", "source", src.Name())
 
-		if err := w.broker.SendMetrics(ctx, []models.Metric{metric}); err != nil {
-			w.log.Error("failed to send cached metric")
-		}
+	if cacheable, ok := src.(source.CacheableSource); ok && w.sendCached(ctx, cacheable) {
 		return
 	}
 
-	uptimePercent, err := checkUptime("https://www.google.com", 5*time.Second)
+	metrics, err := src.Collect(ctx)
 	if err != nil {
-		w.log.Error("failed to check uptime", "error", err)
-		uptimePercent = 0
+		w.log.Error("failed to collect metrics", "source", src.Name(), "error", err)
 	}
 
-	metric := models.Metric{
-		Source:      "UptimeChecker",
-		Name:        "availability_percent",
-		Value:       uptimePercent,
-		Labels:      map[string]any{"site": "google.com"},
-		CollectedAt: time.Now(),
+	if len(metrics) == 0 {
+		return
 	}
 
-	if err := w.broker.SendMetrics(ctx, []models.Metric{metric}); err != nil {
-		w.log.Error("failed to send uptime metric", "error", err)
+	if err := w.broker.SendMetrics(ctx, metrics); err != nil {
+		w.log.Error("failed to send metrics", "source", src.Name(), "error", err)
 		return
 	}
 
-	w.log.Info("successfully collected and sended uptime metric")
+	w.log.Info("successfully collected and sent metrics", "source", src.Name(), "count", len(metrics))
 }
 
-func (w *Worker) collectGithubMetrics(ctx context.Context) {
-	w.log.Info("collecting github metrics...", "repo", w.githubRepo)
-
-	cachedMetric, err := w.cacheClient.GetCachedMetric(ctx, "GitHub", "stargazers_count")
-	if err != nil {
-		w.log.Warn("failed to get from cache, falling back to API", "error", err)
-	} else if cachedMetric != nil {
-		w.log.Info("using cached github metric", "stars", cachedMetric.Value)
-		metric := *cachedMetric
-
-		metric.CollectedAt = time.Now()
-		if metric.Labels == nil {
-			metric.Labels = map[string]any{}
-		}
-		metric.Labels["cached"] = "true"
-
-		if err := w.broker.SendMetrics(ctx, []models.Metric{metric}); err != nil {
-			w.log.Error("failed to send cached metric")
-		}
-		return
+func (w *Worker) sendCached(ctx context.Context, src source.CacheableSource) bool {
+	if w.cacheClient == nil {
+		return false
 	}
 
-	info, err := w.githubClient.GetRepoInfo(ctx, w.githubRepo)
-	if err != nil {
-		w.log.Error("failed to get github repo info", "error", err)
-		return
+	key, ok := src.CacheKey()
+	if !ok {
+		return false
 	}
 
-	metric := models.Metric{
-		Source:      "GitHub",
-		Name:        "stargazers_count",
-		Value:       float64(info.StargazersCount),
-		Labels:      map[string]any{"repository": w.githubRepo},
-		CollectedAt: time.Now(),
+	cachedMetric, err := w.cacheClient.GetCachedMetric(ctx, key.Source, key.Name)
+	if err != nil {
+		w.log.Warn("failed to get from cache, falling back to API", "source", src.Name(), "error", err)
+		return false
 	}
 
-	if err := w.broker.SendMetrics(ctx, []models.Metric{metric}); err != nil {
-		w.log.Error("failed to send github metric", "error", err)
-	} else {
-		w.log.Info("successfully collected github metric", "stars", info.StargazersCount)
+	if cachedMetric == nil {
+		return false
 	}
-}
-
-func (w *Worker) collectOpenWeatherMetrics(ctx context.Context) {
-	w.log.Info("collecting open weather metrics...", "city", w.weatherCity)
-
-	cachedMetric, err := w.cacheClient.GetCachedMetric(ctx, "OpenWeatherMap", "temperature_celsius")
-	if err != nil {
-		w.log.Warn("failed to get from cache, falling back to API", "error", err)
-	} else if cachedMetric != nil {
-		w.log.Info("using cached openweather metric", "temperature", cachedMetric.Value)
-		metric := *cachedMetric
-
-		metric.CollectedAt = time.Now()
-		if metric.Labels == nil {
-			metric.Labels = map[string]any{}
-		}
-		metric.Labels["cached"] = "true"
 
-		if err := w.broker.SendMetrics(ctx, []models.Metric{metric}); err != nil {
-			w.log.Error("failed to send cached metric")
+	for k, v := range key.Labels {
+		if label, ok := cachedMetric.Labels[k]; !ok || fmt.Sprint(label) != v {
+			return false
 		}
-		return
 	}
 
-	data, err := w.weatherClient.GetCurrentTemperature(ctx, w.weatherCity)
-	if err != nil {
-		w.log.Error("failed to get weather data", "error", err)
-		return
-	}
+	w.log.Info("using cached metric", "source", src.Name(), "name", key.Name, "value", cachedMetric.Value)
+	metric := *cachedMetric
 
-	metric := models.Metric{
-		Source:      "OpenWeatherMap",
-		Name:        "temperature_celsius",
-		Value:       data.Main.Temp,
-		Labels:      map[string]any{"city": w.weatherCity},
-		CollectedAt: time.Now(),
+	// The cached metric is a new observation, so it gets an event ID of its
+	// own from its new timestamp.
+	metric.EventID = ""
+	metric.CollectedAt = time.Now()
+	if metric.Labels == nil {
+		metric.Labels = map[string]any{}
 	}
+	metric.Labels["cached"] = "true"
 
 	if err := w.broker.SendMetrics(ctx, []models.Metric{metric}); err != nil {
-		w.log.Error("failed to send weather metric", "error", err)
-	} else {
-		w.log.Info("successfully collected weather metric", "temperature", data.Main.Temp)
+		w.log.Error("failed to send cached metric", "source", src.Name(), "error", err)
 	}
+
+	return true
 }
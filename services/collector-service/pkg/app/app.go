@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/server"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/source"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/spool"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/worker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/grpc"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/nats"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/rabbitmq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Config is aliased so commands outside this module can load and adjust the
+// collector configuration.
+type Config = config.Config
+
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// NewBroker creates the broker selected by broker.type.
+func NewBroker(cfg config.BrokerConfig, log logger.Logger) (broker.MessageBroker, error) {
+	switch cfg.Type {
+	case "kafka":
+		producer, err := kafka.NewProducer(cfg.Kafka)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka producer: %w", err)
+		}
+		log.Info("kafka broker created", "brokers", cfg.Kafka.Brokers, "topic", cfg.Kafka.Topic, "balancer", cfg.Kafka.Balancer)
+		return kafka.NewKafkaBroker(producer), nil
+	case "nats":
+		producer, err := nats.NewProducer(cfg.NATS.URL, cfg.NATS.Stream, cfg.NATS.Subject, cfg.NATS.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create nats producer: %w", err)
+		}
+		log.Info("nats broker created", "url", cfg.NATS.URL, "stream", cfg.NATS.Stream, "subject", cfg.NATS.Subject)
+		return nats.NewNATSBroker(producer), nil
+	case "rabbitmq":
+		producer, err := rabbitmq.NewProducer(cfg.RabbitMQ.URL, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.RoutingKey, cfg.RabbitMQ.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rabbitmq producer: %w", err)
+		}
+		log.Info("rabbitmq broker created", "exchange", cfg.RabbitMQ.Exchange, "routing_key", cfg.RabbitMQ.RoutingKey)
+		return rabbitmq.NewRabbitMQBroker(producer), nil
+	default:
+		return nil, UnsupportedBrokerError{Type: cfg.Type}
+	}
+}
+
+// App is the collector pipeline: the ingest server with its protocol
+// listeners and the worker polling the configured sources. The standalone
+// service and the all-in-one command both run it.
+type App struct {
+	broker broker.MessageBroker
+	srv    *server.Server
+	wrk    *worker.Worker
+	log    logger.Logger
+}
+
+// New wires the pipeline around msgBroker and takes ownership of it.
+func New(cfg *Config, msgBroker broker.MessageBroker, log logger.Logger, reg *prometheus.Registry) (*App, error) {
+	m := metrics.NewMetrics(reg)
+
+	if cfg.Broker.Spool.Enabled {
+		spoolBroker, err := spool.NewBroker(msgBroker, cfg.Broker.Spool, log, m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spool: %w", err)
+		}
+		msgBroker = spoolBroker
+		log.Info("on-disk spool enabled", "dir", cfg.Broker.Spool.Dir)
+	}
+
+	srv := server.New(cfg.Server, cfg.Ingest, log, msgBroker, reg, m)
+
+	cacheClient, err := grpc.NewCacheClient(cfg.Urls.CacheService)
+	if err != nil {
+		log.Warn("cache service unavailable, proceeding without cache", "error", err)
+		cacheClient = nil
+	}
+
+	wrk := worker.New(msgBroker, cacheClient, log, cfg.Worker.PollInterval)
+
+	for _, sourceConfig := range cfg.Sources {
+		src, err := source.New(sourceConfig, cfg)
+		if err != nil {
+			msgBroker.Close()
+			return nil, fmt.Errorf("failed to create metric source %s: %w", sourceConfig.Type, err)
+		}
+
+		wrk.Schedule(src, sourceConfig.Interval)
+		log.Info("metric source registered", "source", src.Name(), "type", sourceConfig.Type)
+	}
+
+	return &App{
+		broker: msgBroker,
+		srv:    srv,
+		wrk:    wrk,
+		log:    log,
+	}, nil
+}
+
+// Run serves until ctx is cancelled or the server fails, then shuts down and
+// closes the broker.
+func (a *App) Run(ctx context.Context) error {
+	errCh := make(chan error, 2)
+
+	go func() {
+		if err := a.srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("server failed to start: %w", err)
+		}
+	}()
+
+	go func() {
+		if err := a.srv.StartListeners(); err != nil {
+			errCh <- fmt.Errorf("listener failed to start: %w", err)
+		}
+	}()
+
+	workerCtx, cancelWorker := context.WithCancel(ctx)
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		a.wrk.Start(workerCtx)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errCh:
+	}
+
+	cancelWorker()
+	<-workerDone
+
+	a.log.Info("server is shutting down...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+
+	if err := a.srv.Shutdown(shutdownCtx); err != nil {
+		a.log.Error("server shutdown failed", "error", err)
+	}
+
+	if err := a.broker.Close(); err != nil {
+		a.log.Error("failed to close broker", "error", err)
+	}
+
+	return runErr
+}
@@ -0,0 +1,11 @@
+package app
+
+import "fmt"
+
+type UnsupportedBrokerError struct {
+	Type string
+}
+
+func (e UnsupportedBrokerError) Error() string {
+	return fmt.Sprintf("unsupported broker type '%s'", e.Type)
+}
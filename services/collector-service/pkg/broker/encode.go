@@ -0,0 +1,156 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/event"
+	metricspb "github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Encoding is the wire format of message payloads.
+type Encoding string
+
+const (
+	EncodingJSON     Encoding = "json"
+	EncodingProtobuf Encoding = "protobuf"
+)
+
+const (
+	// SchemaVersion is the version of the message payload format, sent in
+	// the protobuf envelope and as a message header.
+	SchemaVersion = 1
+
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// ParseEncoding defaults to JSON, which every consumer understands, so
+// producers switch to protobuf only once consumers are upgraded.
+func ParseEncoding(name string) (Encoding, error) {
+	switch Encoding(name) {
+	case "", EncodingJSON:
+		return EncodingJSON, nil
+	case EncodingProtobuf:
+		return EncodingProtobuf, nil
+	default:
+		return "", UnknownEncodingError{Encoding: name}
+	}
+}
+
+// ContentType is sent with every message so consumers know how to decode it.
+func (e Encoding) ContentType() string {
+	if e == EncodingProtobuf {
+		return ContentTypeProtobuf
+	}
+
+	return ContentTypeJSON
+}
+
+// MarshalMetrics serialises every metric in the given encoding. Payloads of
+// metrics that could not be serialised are nil and reported in the returned
+// error, which is nil when all of them succeeded.
+func MarshalMetrics(metrics []models.Metric, encoding Encoding) ([][]byte, *SerialisationError) {
+	payloads := make([][]byte, len(metrics))
+	var serErr SerialisationError
+
+	for i, metric := range metrics {
+		metric.EventID = EventID(metric)
+
+		var (
+			msgBytes []byte
+			err      error
+		)
+		if encoding == EncodingProtobuf {
+			msgBytes, err = proto.Marshal(NewEnvelope(metric))
+		} else {
+			msgBytes, err = json.Marshal(metric)
+		}
+		if err != nil {
+			serErr.FailedCount++
+			serErr.FailedIndexes = append(serErr.FailedIndexes, i)
+			serErr.Errors = append(serErr.Errors, fmt.Errorf("metric %d %s/%s: failed to marshal metric: %w",
+				i,
+				metric.Source,
+				metric.Name,
+				err,
+			))
+			continue
+		}
+
+		payloads[i] = msgBytes
+		serErr.SuccessfullCount++
+	}
+
+	if serErr.FailedCount > 0 {
+		return payloads, &serErr
+	}
+
+	return payloads, nil
+}
+
+// EventID returns the ID a metric is sent with, deriving it for metrics
+// that do not carry one yet.
+func EventID(metric models.Metric) string {
+	if metric.EventID != "" {
+		return metric.EventID
+	}
+
+	return event.ID(metric.Source, metric.Name, metric.Labels, metric.CollectedAt)
+}
+
+// NewEnvelope converts a metric to its protobuf envelope. Label values of
+// types the envelope has no field for are sent as strings.
+func NewEnvelope(metric models.Metric) *metricspb.MetricEnvelope {
+	labels := make(map[string]*metricspb.LabelValue, len(metric.Labels))
+	for key, value := range metric.Labels {
+		labels[key] = labelValue(value)
+	}
+
+	return &metricspb.MetricEnvelope{
+		SchemaVersion: SchemaVersion,
+		Metric: &metricspb.Metric{
+			Source:      metric.Source,
+			Name:        metric.Name,
+			Value:       metric.Value,
+			Labels:      labels,
+			CollectedAt: timestamppb.New(metric.CollectedAt),
+			EventId:     metric.EventID,
+			Unit:        metric.Unit,
+			Description: metric.Description,
+		},
+	}
+}
+
+func labelValue(value any) *metricspb.LabelValue {
+	switch v := value.(type) {
+	case string:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_StringValue{StringValue: v}}
+	case bool:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_BoolValue{BoolValue: v}}
+	case int:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_IntValue{IntValue: int64(v)}}
+	case int32:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_IntValue{IntValue: v}}
+	case uint32:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_IntValue{IntValue: int64(v)}}
+	case float32:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_DoubleValue{DoubleValue: v}}
+	default:
+		return &metricspb.LabelValue{Value: &metricspb.LabelValue_StringValue{StringValue: fmt.Sprint(v)}}
+	}
+}
+
+// SeriesKey identifies the series a metric belongs to. Brokers that
+// partition use it so every update of a series lands on the same partition
+// and stays in order.
+func SeriesKey(metric models.Metric) []byte {
+	return event.SeriesKey(metric.Source, metric.Name, metric.Labels)
+}
@@ -8,6 +8,7 @@ import (
 type SerialisationError struct {
 	SuccessfullCount int
 	FailedCount      int
+	FailedIndexes    []int
 	Errors           []error
 }
 
@@ -24,3 +25,11 @@ func (e *SerialisationError) Error() string {
 
 	return sb.String()
 }
+
+type UnknownEncodingError struct {
+	Encoding string
+}
+
+func (e UnknownEncodingError) Error() string {
+	return fmt.Sprintf("unknown message encoding '%s'", e.Encoding)
+}
@@ -0,0 +1,48 @@
+package broker
+
+import (
+	"context"
+	"regexp"
+)
+
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+var traceParentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// TraceContext is the W3C trace context of the request a batch came from.
+// Brokers forward it with every message so consumers can continue the trace.
+type TraceContext struct {
+	TraceParent string
+	TraceState  string
+}
+
+type traceContextKey struct{}
+
+// ContextWithTrace attaches tc to ctx if its traceparent is well formed.
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	if !validTraceParent(tc.TraceParent) {
+		return ctx
+	}
+
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// validTraceParent rejects the reserved version ff and all-zero IDs, which
+// the W3C spec declares invalid.
+func validTraceParent(s string) bool {
+	if !traceParentPattern.MatchString(s) {
+		return false
+	}
+
+	return s[:2] != "ff" &&
+		s[3:35] != "00000000000000000000000000000000" &&
+		s[36:52] != "0000000000000000"
+}
@@ -53,6 +53,7 @@ func (c *CacheClient) GetCachedMetric(ctx context.Context, source, name string)
 	}
 
 	return &models.Metric{
+		EventID:     resp.Metric.EventId,
 		Source:      resp.Metric.Source,
 		Name:        resp.Metric.Name,
 		Value:       resp.Metric.Value,
@@ -0,0 +1,12 @@
+package kafka
+
+import "fmt"
+
+type UnknownOptionError struct {
+	Option string
+	Value  string
+}
+
+func (e UnknownOptionError) Error() string {
+	return fmt.Sprintf("unknown kafka %s '%s'", e.Option, e.Value)
+}
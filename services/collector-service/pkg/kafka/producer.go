@@ -2,51 +2,88 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"time"
 
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/internal/config"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
 	"github.com/segmentio/kafka-go"
 )
 
+const (
+	HeaderSchemaVersion = "schema-version"
+	HeaderProducerID    = "producer-id"
+	HeaderContentType   = "content-type"
+)
+
 type Producer struct {
-	writer *kafka.Writer
+	writer     *kafka.Writer
+	producerID string
+	encoding   broker.Encoding
 }
 
-func NewProducer(brokers []string, topic string) *Producer {
+// NewProducer creates a producer keying every message by its series, so with
+// a hashing balancer all updates of a series keep their order on one
+// partition.
+func NewProducer(cfg config.KafkaConfig) (*Producer, error) {
+	balancer, err := parseBalancer(cfg.Balancer)
+	if err != nil {
+		return nil, err
+	}
+
+	compression, err := parseCompression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	acks, err := parseRequiredAcks(cfg.RequiredAcks)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding, err := broker.ParseEncoding(cfg.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	producerID := cfg.ProducerID
+	if producerID == "" {
+		if producerID, err = os.Hostname(); err != nil {
+			producerID = "collector-service"
+		}
+	}
+
 	writer := &kafka.Writer{
-		Addr:         kafka.TCP(brokers...),
-		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic,
+		Balancer:     balancer,
+		Compression:  compression,
+		RequiredAcks: acks,
+		BatchSize:    cfg.BatchSize,
+		BatchBytes:   cfg.BatchBytes,
+		BatchTimeout: cfg.BatchTimeout,
 		WriteTimeout: 10 * time.Second,
 		ReadTimeout:  10 * time.Second,
 	}
-	return &Producer{writer: writer}
+	return &Producer{writer: writer, producerID: producerID, encoding: encoding}, nil
 }
 
 func (p *Producer) ProduceMetrics(ctx context.Context, metrics []models.Metric) error {
-	kafkaMessages := make([]kafka.Message, 0, len(metrics))
-	var serErr broker.SerialisationError
-
-	for i, metric := range metrics {
-		msgBytes, err := json.Marshal(metric)
-		if err != nil {
-			serErr.FailedCount++
-			serErr.Errors = append(serErr.Errors, fmt.Errorf("metric %d %s/%s: failed to marshal metric: %w",
-				i,
-				metric.Source,
-				metric.Name,
-				err,
-			))
-			continue
-		}
+	payloads, serErr := broker.MarshalMetrics(metrics, p.encoding)
+	headers := p.headers(ctx)
 
-		kafkaMessages = append(kafkaMessages, kafka.Message{
-			Value: msgBytes,
-		})
-		serErr.SuccessfullCount++
+	kafkaMessages := make([]kafka.Message, 0, len(payloads))
+	for i, payload := range payloads {
+		if payload != nil {
+			kafkaMessages = append(kafkaMessages, kafka.Message{
+				Key:     broker.SeriesKey(metrics[i]),
+				Value:   payload,
+				Headers: headers,
+			})
+		}
 	}
 
 	if len(kafkaMessages) > 0 {
@@ -55,8 +92,8 @@ func (p *Producer) ProduceMetrics(ctx context.Context, metrics []models.Metric)
 		}
 	}
 
-	if serErr.FailedCount > 0 {
-		return &serErr
+	if serErr != nil {
+		return serErr
 	}
 
 	return nil
@@ -65,3 +102,70 @@ func (p *Producer) ProduceMetrics(ctx context.Context, metrics []models.Metric)
 func (p *Producer) Close() error {
 	return p.writer.Close()
 }
+
+// headers are shared by every message of a batch, which all come from the
+// same request.
+func (p *Producer) headers(ctx context.Context) []kafka.Header {
+	headers := []kafka.Header{
+		{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(broker.SchemaVersion))},
+		{Key: HeaderProducerID, Value: []byte(p.producerID)},
+		{Key: HeaderContentType, Value: []byte(p.encoding.ContentType())},
+	}
+
+	if tc, ok := broker.TraceFromContext(ctx); ok {
+		headers = append(headers, kafka.Header{Key: broker.TraceParentHeader, Value: []byte(tc.TraceParent)})
+		if tc.TraceState != "" {
+			headers = append(headers, kafka.Header{Key: broker.TraceStateHeader, Value: []byte(tc.TraceState)})
+		}
+	}
+
+	return headers
+}
+
+func parseBalancer(name string) (kafka.Balancer, error) {
+	switch name {
+	case "", "hash":
+		return &kafka.Hash{}, nil
+	case "murmur2":
+		// Matches the default partitioner of the Java client.
+		return kafka.Murmur2Balancer{}, nil
+	case "crc32":
+		return kafka.CRC32Balancer{}, nil
+	case "round_robin":
+		return &kafka.RoundRobin{}, nil
+	case "least_bytes":
+		return &kafka.LeastBytes{}, nil
+	default:
+		return nil, UnknownOptionError{Option: "balancer", Value: name}
+	}
+}
+
+func parseCompression(name string) (kafka.Compression, error) {
+	switch name {
+	case "", "none":
+		return 0, nil
+	case "gzip":
+		return kafka.Gzip, nil
+	case "snappy":
+		return kafka.Snappy, nil
+	case "lz4":
+		return kafka.Lz4, nil
+	case "zstd":
+		return kafka.Zstd, nil
+	default:
+		return 0, UnknownOptionError{Option: "compression", Value: name}
+	}
+}
+
+func parseRequiredAcks(name string) (kafka.RequiredAcks, error) {
+	switch name {
+	case "", "all":
+		return kafka.RequireAll, nil
+	case "one":
+		return kafka.RequireOne, nil
+	case "none":
+		return kafka.RequireNone, nil
+	default:
+		return 0, UnknownOptionError{Option: "required_acks", Value: name}
+	}
+}
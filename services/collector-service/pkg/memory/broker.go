@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+// MemoryBroker publishes metrics to an in-process Bus, encoded the same way
+// as for the network brokers so consumers decode them unchanged.
+type MemoryBroker struct {
+	bus *Bus
+}
+
+func NewMemoryBroker(bus *Bus) *MemoryBroker {
+	return &MemoryBroker{
+		bus: bus,
+	}
+}
+
+func (b *MemoryBroker) SendMetrics(ctx context.Context, metrics []models.Metric) error {
+	payloads, serErr := broker.MarshalMetrics(metrics, broker.EncodingJSON)
+
+	for _, payload := range payloads {
+		if payload == nil {
+			continue
+		}
+
+		if err := b.bus.Publish(ctx, payload); err != nil {
+			return fmt.Errorf("failed to publish message to in-memory bus: %w", err)
+		}
+	}
+
+	if serErr != nil {
+		return serErr
+	}
+
+	return nil
+}
+
+// Close leaves the bus open, it is shared with the consumers and closed by
+// its owner.
+func (b *MemoryBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultBufferSize = 10000
+
+// Bus is an in-process message bus. Every consumer group receives its own
+// copy of each published message, while subscriptions sharing a group compete
+// for them, the same way Kafka consumer groups do. Messages published before
+// a group subscribes, or while it has no members, are not delivered to it.
+type Bus struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	groups map[string]*group
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// group is removed from the bus when its last member leaves; removed closes
+// then, so publishers blocked on its full queue move on.
+type group struct {
+	queue   chan []byte
+	members int
+	removed chan struct{}
+}
+
+func NewBus(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	return &Bus{
+		bufferSize: bufferSize,
+		groups:     make(map[string]*group),
+		done:       make(chan struct{}),
+	}
+}
+
+// Subscribe joins the consumer group, creating it on first use.
+func (b *Bus) Subscribe(name string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	g, ok := b.groups[name]
+	if !ok {
+		g = &group{
+			queue:   make(chan []byte, b.bufferSize),
+			removed: make(chan struct{}),
+		}
+		b.groups[name] = g
+	}
+	g.members++
+
+	return &Subscription{
+		bus:   b,
+		name:  name,
+		group: g,
+		done:  make(chan struct{}),
+	}
+}
+
+// Publish delivers the payload to every consumer group. It blocks while a
+// group's buffer is full, so a slow pipeline slows the publisher down instead
+// of losing messages.
+func (b *Bus) Publish(ctx context.Context, payload []byte) error {
+	b.mu.RLock()
+	groups := make([]*group, 0, len(b.groups))
+	for _, g := range b.groups {
+		groups = append(groups, g)
+	}
+	b.mu.RUnlock()
+
+	for _, g := range groups {
+		select {
+		case <-b.done:
+			return ClosedError{}
+		default:
+		}
+
+		select {
+		case g.queue <- payload:
+		case <-g.removed:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.done:
+			return ClosedError{}
+		}
+	}
+
+	return nil
+}
+
+// leave removes a member from the group and drops the group with its
+// buffered messages once no members are left.
+func (b *Bus) leave(name string, g *group) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	g.members--
+	if g.members > 0 {
+		return
+	}
+
+	if b.groups[name] == g {
+		delete(b.groups, name)
+	}
+	close(g.removed)
+}
+
+// Close stops delivery to every subscription. Messages still buffered are
+// discarded.
+func (b *Bus) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+
+	return nil
+}
+
+type Subscription struct {
+	bus   *Bus
+	name  string
+	group *group
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *Subscription) Receive(ctx context.Context) ([]byte, error) {
+	select {
+	case payload := <-s.group.queue:
+		return payload, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ClosedError{}
+	case <-s.bus.done:
+		return nil, ClosedError{}
+	}
+}
+
+// Close leaves the consumer group. The group keeps buffering messages for its
+// remaining members; once the last one leaves, the group and its buffered
+// messages are dropped.
+func (s *Subscription) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.bus.leave(s.name, s.group)
+	})
+
+	return nil
+}
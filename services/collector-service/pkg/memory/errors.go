@@ -0,0 +1,7 @@
+package memory
+
+type ClosedError struct{}
+
+func (e ClosedError) Error() string {
+	return "in-memory bus is closed"
+}
@@ -0,0 +1,25 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+type NATSBroker struct {
+	producer *Producer
+}
+
+func NewNATSBroker(producer *Producer) *NATSBroker {
+	return &NATSBroker{
+		producer: producer,
+	}
+}
+
+func (n *NATSBroker) SendMetrics(ctx context.Context, metrics []models.Metric) error {
+	return n.producer.ProduceMetrics(ctx, metrics)
+}
+
+func (n *NATSBroker) Close() error {
+	return n.producer.Close()
+}
@@ -0,0 +1,130 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const setupTimeout = 10 * time.Second
+
+// Message headers. Consumers look the content type up under the name HTTP
+// uses, since NATS headers are case-sensitive.
+const (
+	HeaderContentType   = "Content-Type"
+	HeaderSchemaVersion = "schema-version"
+)
+
+type Producer struct {
+	conn     *nats.Conn
+	js       jetstream.JetStream
+	subject  string
+	encoding broker.Encoding
+}
+
+// NewProducer connects to NATS and makes sure the stream capturing subject
+// exists, so consumers can attach durable consumers to it.
+func NewProducer(url, stream, subject, encodingName string) (*Producer, error) {
+	encoding, err := broker.ParseEncoding(encodingName)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := nats.Connect(url, nats.Name("collector-service"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     stream,
+		Subjects: []string{subject},
+		Storage:  jetstream.FileStorage,
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", stream, err)
+	}
+
+	return &Producer{conn: conn, js: js, subject: subject, encoding: encoding}, nil
+}
+
+// ProduceMetrics publishes asynchronously and waits until JetStream has
+// acknowledged every message of the batch.
+func (p *Producer) ProduceMetrics(ctx context.Context, metrics []models.Metric) error {
+	payloads, serErr := broker.MarshalMetrics(metrics, p.encoding)
+	header := p.header(ctx)
+
+	futures := make([]jetstream.PubAckFuture, 0, len(payloads))
+	for i, payload := range payloads {
+		if payload == nil {
+			continue
+		}
+
+		// The event ID lets JetStream drop redelivered duplicates and gives
+		// consumers a message key.
+		msgHeader := maps.Clone(header)
+		msgHeader.Set(jetstream.MsgIDHeader, broker.EventID(metrics[i]))
+
+		future, err := p.js.PublishMsgAsync(&nats.Msg{
+			Subject: p.subject,
+			Data:    payload,
+			Header:  msgHeader,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish message to NATS: %w", err)
+		}
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return fmt.Errorf("failed to sent %d messages to NATS: %w", len(futures), err)
+		case <-ctx.Done():
+			return fmt.Errorf("failed to sent %d messages to NATS: %w", len(futures), ctx.Err())
+		}
+	}
+
+	if serErr != nil {
+		return serErr
+	}
+
+	return nil
+}
+
+// header holds what every message of a batch shares, since they all come from
+// the same request.
+func (p *Producer) header(ctx context.Context) nats.Header {
+	header := nats.Header{}
+	header.Set(HeaderContentType, p.encoding.ContentType())
+	header.Set(HeaderSchemaVersion, strconv.Itoa(broker.SchemaVersion))
+
+	if tc, ok := broker.TraceFromContext(ctx); ok {
+		header.Set(broker.TraceParentHeader, tc.TraceParent)
+		if tc.TraceState != "" {
+			header.Set(broker.TraceStateHeader, tc.TraceState)
+		}
+	}
+
+	return header
+}
+
+func (p *Producer) Close() error {
+	return p.conn.Drain()
+}
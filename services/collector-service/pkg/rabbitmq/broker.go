@@ -0,0 +1,25 @@
+package rabbitmq
+
+import (
+	"context"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+)
+
+type RabbitMQBroker struct {
+	producer *Producer
+}
+
+func NewRabbitMQBroker(producer *Producer) *RabbitMQBroker {
+	return &RabbitMQBroker{
+		producer: producer,
+	}
+}
+
+func (r *RabbitMQBroker) SendMetrics(ctx context.Context, metrics []models.Metric) error {
+	return r.producer.ProduceMetrics(ctx, metrics)
+}
+
+func (r *RabbitMQBroker) Close() error {
+	return r.producer.Close()
+}
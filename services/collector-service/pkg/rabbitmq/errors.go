@@ -0,0 +1,11 @@
+package rabbitmq
+
+import "fmt"
+
+type NackError struct {
+	Count int
+}
+
+func (e NackError) Error() string {
+	return fmt.Sprintf("RabbitMQ rejected a batch of %d messages", e.Count)
+}
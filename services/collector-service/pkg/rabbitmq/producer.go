@@ -0,0 +1,153 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/broker"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/collector-service/pkg/models"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Producer publishes metrics to a durable direct exchange with publisher
+// confirms enabled. A lost connection is re-established on the next publish.
+type Producer struct {
+	url        string
+	exchange   string
+	routingKey string
+	encoding   broker.Encoding
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+func NewProducer(url, exchange, routingKey, encodingName string) (*Producer, error) {
+	encoding, err := broker.ParseEncoding(encodingName)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Producer{
+		url:        url,
+		exchange:   exchange,
+		routingKey: routingKey,
+		encoding:   encoding,
+	}
+
+	if err := p.connect(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Producer) connect() error {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(p.exchange, amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to declare exchange %s: %w", p.exchange, err)
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	p.conn = conn
+	p.ch = ch
+
+	return nil
+}
+
+func (p *Producer) ProduceMetrics(ctx context.Context, metrics []models.Metric) error {
+	payloads, serErr := broker.MarshalMetrics(metrics, p.encoding)
+	headers := publishingHeaders(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ch == nil || p.ch.IsClosed() {
+		if p.conn != nil {
+			p.conn.Close()
+		}
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+
+	confirms := make([]*amqp.DeferredConfirmation, 0, len(payloads))
+	for i, payload := range payloads {
+		if payload == nil {
+			continue
+		}
+
+		confirm, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, p.exchange, p.routingKey, false, false, amqp.Publishing{
+			ContentType:  p.encoding.ContentType(),
+			MessageId:    broker.EventID(metrics[i]),
+			Headers:      headers,
+			DeliveryMode: amqp.Persistent,
+			Body:         payload,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish message to RabbitMQ: %w", err)
+		}
+		confirms = append(confirms, confirm)
+	}
+
+	for _, confirm := range confirms {
+		acked, err := confirm.WaitContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sent %d messages to RabbitMQ: %w", len(confirms), err)
+		}
+		if !acked {
+			return NackError{Count: len(confirms)}
+		}
+	}
+
+	if serErr != nil {
+		return serErr
+	}
+
+	return nil
+}
+
+// HeaderSchemaVersion is set next to the content type of every message.
+const HeaderSchemaVersion = "schema-version"
+
+// publishingHeaders are shared by every message of a batch, which all come
+// from the same request.
+func publishingHeaders(ctx context.Context) amqp.Table {
+	headers := amqp.Table{HeaderSchemaVersion: int32(broker.SchemaVersion)}
+
+	if tc, ok := broker.TraceFromContext(ctx); ok {
+		headers[broker.TraceParentHeader] = tc.TraceParent
+		if tc.TraceState != "" {
+			headers[broker.TraceStateHeader] = tc.TraceState
+		}
+	}
+
+	return headers
+}
+
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+
+	return p.conn.Close()
+}
@@ -10,12 +10,8 @@ import (
 	"syscall"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/config"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/metrics"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/processor"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/consumer"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/app"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/logger"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/notifier"
 	"github.com/fsnotify/fsnotify"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -53,41 +49,32 @@ func main() {
 	})
 
 	cfgMutex.RLock()
-	emailConfig := cfg.Email
-	brokerConfig := cfg.Broker
-	serverConfig := cfg.Server
+	appConfig := *cfg
 	cfgMutex.RUnlock()
 
 	reg := prometheus.NewRegistry()
-	m := metrics.NewMetrics(reg)
 
-	var msgCons consumer.MessageConsumer
-
-	switch brokerConfig.Type {
-	case "kafka":
-		msgCons = kafka.NewKafkaConsumer(brokerConfig.Kafka.Brokers, brokerConfig.Kafka.Topic, brokerConfig.Kafka.GroupID)
-	default:
-		log.Error("unsupported consumer type", "type", brokerConfig.Type)
+	msgCons, err := app.NewConsumer(appConfig.Broker)
+	if err != nil {
+		log.Error("failed to create consumer", "error", err)
 		os.Exit(1)
 	}
-	defer msgCons.Close()
 
-	emailNotifier := notifier.NewEmailNotifier(
-		emailConfig.SMTPHost,
-		emailConfig.SMTPPort,
-		emailConfig.From,
-		emailConfig.Username,
-		emailConfig.Password,
-		emailConfig.To,
-		m,
-	)
+	dlq := app.NewDeadLetterQueue(appConfig.Broker)
+	if dlq == nil {
+		log.Warn("dead-letter queue disabled, messages that cannot be handled will be dropped")
+	}
 
-	proc := processor.New(msgCons, emailNotifier, log, m)
+	notification, err := app.New(&appConfig, msgCons, dlq, log, reg)
+	if err != nil {
+		log.Error("failed to start notification", "error", err)
+		os.Exit(1)
+	}
 
 	go func() {
 		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-		log.Info("metrics server listening", "port", serverConfig.Port)
-		if err := http.ListenAndServe(":"+serverConfig.Port, nil); err != nil {
+		log.Info("metrics server listening", "port", appConfig.Server.Port)
+		if err := http.ListenAndServe(":"+appConfig.Server.Port, nil); err != nil {
 			log.Error("failed to start metrics server", "error", err)
 			os.Exit(1)
 		}
@@ -96,14 +83,17 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	log.Info("starting notification-service")
-	proc.Start(ctx)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+		cancel()
+	}()
 
-	cancel()
+	if err := notification.Run(ctx); err != nil {
+		log.Error("failed to release resources", "error", err)
+	}
 
 	log.Info("service gracefully stopped")
 }
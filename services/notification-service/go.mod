@@ -1,14 +1,21 @@
 module github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service
 
-go 1.23.6
+go 1.24.0
 
-require github.com/segmentio/kafka-go v0.4.49
+require (
+	github.com/nats-io/nats.go v1.47.0
+	github.com/rabbitmq/amqp091-go v1.10.0
+	github.com/segmentio/kafka-go v0.4.49
+	google.golang.org/protobuf v1.36.10
+)
 
 require (
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/nats-io/nkeys v0.4.11 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
 	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
 	github.com/prometheus/client_model v0.6.2 // indirect
 	github.com/prometheus/common v0.66.1 // indirect
@@ -21,15 +28,18 @@ require (
 	github.com/subosito/gotenv v1.6.0 // indirect
 	go.yaml.in/yaml/v2 v2.4.2 // indirect
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
-	google.golang.org/protobuf v1.36.8 // indirect
 )
 
 require (
+	github.com/MatTwix/Ultimate-Metrics-Platform/services/shared v0.0.0
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/klauspost/compress v1.18.0 // indirect
-	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/pierrec/lz4/v4 v4.1.16 // indirect
 	github.com/prometheus/client_golang v1.23.2
 	github.com/spf13/viper v1.21.0
 )
+
+replace github.com/MatTwix/Ultimate-Metrics-Platform/services/shared => ../shared
@@ -10,6 +10,7 @@ type Metrics struct {
 	NotificationsSentTotal     *prometheus.CounterVec
 	NotificationsErrorsTotal   *prometheus.CounterVec
 	NotificationsSendDuration  *prometheus.HistogramVec
+	DeadLettersTotal           *prometheus.CounterVec
 }
 
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -32,5 +33,9 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Help:    "Duration of sending notifications",
 			Buckets: prometheus.DefBuckets,
 		}, []string{"notifier_type"}),
+		DeadLettersTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "notification_service_dead_letters_total",
+			Help: "Total number of messages given up on and sent to the dead-letter queue",
+		}, []string{"reason"}),
 	}
 }
@@ -2,64 +2,213 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/config"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/metrics"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/consumer"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/logger"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/notifier"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/retry"
+)
+
+const (
+	// commitBatch and commitInterval bound how many handled messages wait
+	// for a commit and for how long.
+	commitBatch    = 100
+	commitInterval = time.Second
+
+	shutdownCommitTimeout = 5 * time.Second
 )
 
 type Processor struct {
 	consumer consumer.MessageConsumer
 	notifier notifier.Notifier
+	dlq      consumer.DeadLetterQueue
 	stars    map[string]int
 	log      logger.Logger
 	metrics  *metrics.Metrics
+	retry    retry.Policy
 }
 
-func New(consumer consumer.MessageConsumer, notifier notifier.Notifier, log logger.Logger, metrics *metrics.Metrics) *Processor {
+// New creates the processor. dlq may be nil, in which case messages whose
+// notification cannot be sent are logged and dropped.
+func New(consumer consumer.MessageConsumer, notifier notifier.Notifier, dlq consumer.DeadLetterQueue, log logger.Logger, metrics *metrics.Metrics, retryCfg config.RetryConfig) *Processor {
 	return &Processor{
 		consumer: consumer,
 		notifier: notifier,
+		dlq:      dlq,
 		log:      log,
 		stars:    make(map[string]int),
 		metrics:  metrics,
+		retry:    retry.NewPolicy(retryCfg.MaxAttempts, retryCfg.InitialBackoff, retryCfg.MaxBackoff, retryCfg.Multiplier),
 	}
 }
 
+// Start evaluates metrics one at a time. Handled messages are committed in
+// groups, once commitBatch of them are pending or the oldest has waited for
+// commitInterval. A failed notification is retried under the retry policy
+// and then dead-lettered instead of being skipped.
 func (p *Processor) Start(ctx context.Context) {
+	var uncommitted int
+	var deadline time.Time
+	var fetchFailures int
+
 	for {
-		select {
-		case <-ctx.Done():
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if uncommitted > 0 {
+			fetchCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+
+		msg, err := p.consumer.FetchMetric(fetchCtx)
+		cancel()
+
+		var decodeErr consumer.DecodeError
+		switch {
+		case ctx.Err() != nil:
+			p.shutdown(uncommitted)
 			return
+		case errors.Is(err, context.DeadlineExceeded):
+			p.commit(ctx)
+			uncommitted = 0
+		case errors.As(err, &decodeErr):
+			p.metrics.NotificationsErrorsTotal.WithLabelValues("consume", "none").Inc()
+			p.log.Error("failed to decode metric", "error", err)
+
+			letter := consumer.DeadLetter{
+				Key:         decodeErr.Key,
+				Payload:     decodeErr.Payload,
+				ContentType: decodeErr.ContentType,
+				Err:         err,
+				Attempts:    1,
+				FailedAt:    time.Now(),
+			}
+			if !p.deadLetter(ctx, "decode", letter) {
+				return
+			}
+		case err != nil:
+			fetchFailures++
+			p.metrics.NotificationsErrorsTotal.WithLabelValues("consume", "none").Inc()
+			p.log.Error("failed to consume metric", "error", err)
+			p.retry.Wait(ctx, fetchFailures)
 		default:
-			metric, err := p.consumer.ConsumeMetric(ctx)
-			if err != nil {
-				p.metrics.NotificationsErrorsTotal.WithLabelValues("consume", "none").Inc()
-				p.log.Error("failed to consume metric", "error", err)
-				time.Sleep(time.Second)
-				continue
+			fetchFailures = 0
+			p.metrics.NotificationsConsumedTotal.Inc()
+
+			if !p.handle(ctx, msg) {
+				return
 			}
 
-			p.metrics.NotificationsConsumedTotal.Inc()
+			if uncommitted == 0 {
+				deadline = time.Now().Add(commitInterval)
+			}
+
+			uncommitted++
+			if uncommitted >= commitBatch {
+				p.commit(ctx)
+				uncommitted = 0
+			}
+		}
+	}
+}
+
+// handle sends the notifications the metric triggers, retrying under the
+// retry policy before the message is dead-lettered. It returns false only if
+// ctx was cancelled first.
+func (p *Processor) handle(ctx context.Context, msg consumer.Message) bool {
+	metric := msg.Metric
+
+	if metric.Source != "GitHub" || metric.Name != "stargazers_count" {
+		return true
+	}
+
+	repo, _ := metric.Labels["repository"].(string)
+	if repo == "" {
+		repo = "unknown"
+	}
+	newStars := int(metric.Value)
+	oldStars, exists := p.stars[repo]
 
-			if metric.Source == "GitHub" && metric.Name == "stargazers_count" {
-				repo := "golang/go"
-				newStars := int(metric.Value)
-				oldStars, exists := p.stars[repo]
-
-				if exists && newStars > oldStars {
-					err := p.notifier.NotifyStarInrcease(repo, oldStars, newStars)
-					if err != nil {
-						p.log.Error("failed to notify", "error", err)
-						time.Sleep(time.Second)
-					}
-					p.log.Info("notification succeccfully sended", "repo", repo, "stars_incrementations", newStars-oldStars)
+	if exists && newStars > oldStars {
+		for attempt := 1; ; attempt++ {
+			err := p.notifier.NotifyStarInrcease(repo, oldStars, newStars)
+			if err == nil {
+				p.log.Info("notification succeccfully sended", "repo", repo, "stars_incrementations", newStars-oldStars)
+				break
+			}
+
+			p.log.Error("failed to notify", "error", err, "attempt", attempt)
+
+			if attempt >= p.retry.MaxAttempts {
+				if !p.deadLetter(ctx, "notify", consumer.DeadLetter{
+					Key:         msg.Key,
+					Payload:     msg.Payload,
+					ContentType: msg.ContentType,
+					Err:         err,
+					Attempts:    attempt,
+					FailedAt:    time.Now(),
+				}) {
+					return false
 				}
+				break
+			}
 
-				p.stars[repo] = newStars
+			if !p.retry.Wait(ctx, attempt) {
+				return false
 			}
 		}
 	}
+
+	p.stars[repo] = newStars
+
+	return true
+}
+
+// deadLetter hands letters to the dead-letter queue, retrying under the
+// retry policy. Letters the queue does not accept, or all of them without a
+// queue, are logged and dropped. It reports false if ctx was cancelled first.
+func (p *Processor) deadLetter(ctx context.Context, reason string, letters ...consumer.DeadLetter) bool {
+	if p.dlq != nil {
+		err := p.retry.Do(ctx, func() error {
+			return p.dlq.Send(ctx, letters...)
+		}, func(err error, attempt int) {
+			p.log.Error("failed to send messages to dead-letter queue", "error", err, "attempt", attempt)
+		})
+		if err == nil {
+			p.log.Warn("messages sent to dead-letter queue", "reason", reason, "count", len(letters))
+			p.metrics.DeadLettersTotal.WithLabelValues(reason).Add(float64(len(letters)))
+
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+
+	for _, letter := range letters {
+		p.log.Error("dropping message that could not be dead-lettered", "error", letter.Err, "attempts", letter.Attempts)
+	}
+	p.metrics.DeadLettersTotal.WithLabelValues(reason).Add(float64(len(letters)))
+
+	return true
+}
+
+func (p *Processor) commit(ctx context.Context) {
+	if err := p.consumer.Commit(ctx); err != nil {
+		p.metrics.NotificationsErrorsTotal.WithLabelValues("commit", "none").Inc()
+		p.log.Error("failed to commit messages, they will be redelivered", "error", err)
+	}
+}
+
+// shutdown commits the messages handled before the processor was stopped.
+func (p *Processor) shutdown(uncommitted int) {
+	if uncommitted == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownCommitTimeout)
+	defer cancel()
+
+	p.commit(ctx)
 }
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/internal/processor"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/nats"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/notifier"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/notification-service/pkg/rabbitmq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config is aliased so commands outside this module can load and adjust the
+// notification configuration.
+type Config = config.Config
+
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// NewConsumer creates the consumer selected by broker.type.
+func NewConsumer(cfg config.BrokerConfig) (consumer.MessageConsumer, error) {
+	switch cfg.Type {
+	case "kafka":
+		return kafka.NewKafkaConsumer(cfg.Kafka.Brokers, cfg.Kafka.Topic, cfg.Kafka.GroupID), nil
+	case "nats":
+		cons, err := nats.NewNATSConsumer(cfg.NATS.URL, cfg.NATS.Stream, cfg.NATS.Subject, cfg.NATS.Durable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create NATS consumer: %w", err)
+		}
+		return cons, nil
+	case "rabbitmq":
+		cons, err := rabbitmq.NewRabbitMQConsumer(cfg.RabbitMQ.URL, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.RoutingKey, cfg.RabbitMQ.Queue, cfg.RabbitMQ.Prefetch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RabbitMQ consumer: %w", err)
+		}
+		return cons, nil
+	default:
+		return nil, UnsupportedBrokerError{Type: cfg.Type}
+	}
+}
+
+// NewDeadLetterQueue creates the queue for messages whose notification
+// cannot be sent. Dead-lettering needs a Kafka topic, so for other brokers,
+// or without broker.kafka.dead_letter_topic, it returns nil and failed
+// messages are dropped.
+func NewDeadLetterQueue(cfg config.BrokerConfig) consumer.DeadLetterQueue {
+	if cfg.Type != "kafka" || cfg.Kafka.DeadLetterTopic == "" {
+		return nil
+	}
+
+	return kafka.NewDeadLetterWriter(cfg.Kafka.Brokers, cfg.Kafka.DeadLetterTopic, cfg.Kafka.Topic, cfg.Kafka.GroupID)
+}
+
+// App is the notification pipeline, sending emails for consumed metrics. The
+// standalone service and the all-in-one command both run it.
+type App struct {
+	consumer consumer.MessageConsumer
+	dlq      consumer.DeadLetterQueue
+	proc     *processor.Processor
+	log      logger.Logger
+}
+
+// New takes ownership of msgCons and dlq. dlq may be nil.
+func New(cfg *Config, msgCons consumer.MessageConsumer, dlq consumer.DeadLetterQueue, log logger.Logger, reg *prometheus.Registry) (*App, error) {
+	m := metrics.NewMetrics(reg)
+
+	emailNotifier := notifier.NewEmailNotifier(
+		cfg.Email.SMTPHost,
+		cfg.Email.SMTPPort,
+		cfg.Email.From,
+		cfg.Email.Username,
+		cfg.Email.Password,
+		cfg.Email.To,
+		m,
+	)
+
+	return &App{
+		consumer: msgCons,
+		dlq:      dlq,
+		proc:     processor.New(msgCons, emailNotifier, dlq, log, m, cfg.Retry),
+		log:      log,
+	}, nil
+}
+
+// Run consumes until ctx is cancelled, then closes the consumer and the
+// dead-letter queue.
+func (a *App) Run(ctx context.Context) error {
+	a.log.Info("starting notification-service")
+	a.proc.Start(ctx)
+
+	errs := []error{a.consumer.Close()}
+	if a.dlq != nil {
+		errs = append(errs, a.dlq.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,36 @@
+package consumer
+
+import "fmt"
+
+type UnsupportedContentTypeError struct {
+	ContentType string
+}
+
+func (e UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported message content type '%s'", e.ContentType)
+}
+
+type UnsupportedSchemaVersionError struct {
+	Version uint32
+}
+
+func (e UnsupportedSchemaVersionError) Error() string {
+	return fmt.Sprintf("unsupported message schema version %d, newest supported is %d", e.Version, SchemaVersion)
+}
+
+// DecodeError reports a message that could not be decoded. Retrying cannot
+// fix it, so it keeps the original payload for the dead-letter queue.
+type DecodeError struct {
+	Key         []byte
+	ContentType string
+	Payload     []byte
+	Err         error
+}
+
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode message: %v", e.Err)
+}
+
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
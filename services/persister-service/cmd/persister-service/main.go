@@ -10,12 +10,7 @@ import (
 	"syscall"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/config"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/database"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/metrics"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/processor"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/repository"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/consumer"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/app"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/logger"
 	"github.com/fsnotify/fsnotify"
 	"github.com/prometheus/client_golang/prometheus"
@@ -54,46 +49,32 @@ func main() {
 	})
 
 	cfgMutex.RLock()
-	dbConfig := cfg.Postgres
-	brokerConfig := cfg.Broker
-	serverConfig := cfg.Server
+	appConfig := *cfg
 	cfgMutex.RUnlock()
 
 	reg := prometheus.NewRegistry()
-	m := metrics.NewMetrics(reg)
 
-	db, err := database.New(dbConfig, m)
+	msgCons, err := app.NewConsumer(appConfig.Broker, appConfig.Batch.Size)
 	if err != nil {
-		log.Error("failed to connect to database", "error", err)
+		log.Error("failed to create consumer", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
-	log.Info("database connected successfully")
 
-	if err := db.RunMigrations(); err != nil {
-		log.Error("failed to run database migrations", "error", err)
-		os.Exit(1)
+	dlq := app.NewDeadLetterQueue(appConfig.Broker)
+	if dlq == nil {
+		log.Warn("dead-letter queue disabled, messages that cannot be stored will be dropped")
 	}
-	log.Info("database migrations applied successfully")
-	var metricsRepo repository.MetricRepository = db
-
-	var msgCons consumer.MessageConsumer
 
-	switch brokerConfig.Type {
-	case "kafka":
-		msgCons = kafka.NewKafkaConsumer(brokerConfig.Kafka.Brokers, brokerConfig.Kafka.Topic, brokerConfig.Kafka.GroupID)
-	default:
-		log.Error("unsupported consumer type", "type", brokerConfig.Type)
+	persister, err := app.New(&appConfig, msgCons, dlq, log, reg)
+	if err != nil {
+		log.Error("failed to start persister", "error", err)
 		os.Exit(1)
 	}
-	defer msgCons.Close()
-
-	proc := processor.NewConsumer(msgCons, metricsRepo, log, m)
 
 	go func() {
 		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-		log.Info("metrics server listening", "port", serverConfig.Port)
-		if err := http.ListenAndServe(":"+serverConfig.Port, nil); err != nil {
+		log.Info("metrics server listening", "port", appConfig.Server.Port)
+		if err := http.ListenAndServe(":"+appConfig.Server.Port, nil); err != nil {
 			log.Error("failed to start metrics server", "error", err)
 			os.Exit(1)
 		}
@@ -102,14 +83,17 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	log.Info("starting persister-service")
-	proc.Start(ctx)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+		cancel()
+	}()
 
-	cancel()
+	if err := persister.Run(ctx); err != nil {
+		log.Error("failed to release resources", "error", err)
+	}
 
 	log.Info("service gracefully stopped")
 }
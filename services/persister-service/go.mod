@@ -1,8 +1,13 @@
 module github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service
 
-go 1.23.6
+go 1.24.0
 
-require github.com/golang-migrate/migrate/v4 v4.19.0
+require (
+	github.com/golang-migrate/migrate/v4 v4.19.0
+	github.com/nats-io/nats.go v1.47.0
+	github.com/rabbitmq/amqp091-go v1.10.0
+	google.golang.org/protobuf v1.36.10
+)
 
 require (
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -14,6 +19,8 @@ require (
 	github.com/klauspost/compress v1.18.0 // indirect
 	github.com/lib/pq v1.10.9 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/nats-io/nkeys v0.4.11 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
 	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
 	github.com/pierrec/lz4/v4 v4.1.16 // indirect
 	github.com/prometheus/client_model v0.6.2 // indirect
@@ -31,10 +38,10 @@ require (
 	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
-	google.golang.org/protobuf v1.36.8 // indirect
 )
 
 require (
+	github.com/MatTwix/Ultimate-Metrics-Platform/services/shared v0.0.0
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
@@ -43,3 +50,5 @@ require (
 	github.com/segmentio/kafka-go v0.4.49
 	github.com/spf13/viper v1.21.0
 )
+
+replace github.com/MatTwix/Ultimate-Metrics-Platform/services/shared => ../shared
@@ -14,6 +14,11 @@ type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Postgres PostgresConfig `mapstructure:"postgres"`
 	Broker   BrokerConfig   `mapstructure:"broker"`
+	Batch    BatchConfig    `mapstructure:"batch"`
+	Retry    RetryConfig    `mapstructure:"retry"`
+
+	Partitioning PartitioningConfig `mapstructure:"partitioning"`
+	Retention    RetentionConfig    `mapstructure:"retention"`
 }
 
 type ServerConfig struct {
@@ -22,14 +27,18 @@ type ServerConfig struct {
 	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
 }
 
+// PostgresConfig describes the database. With Timescale set, metrics are
+// stored in a TimescaleDB hypertable with continuous aggregates instead of
+// the natively partitioned table, and partitioning is left to TimescaleDB.
 type PostgresConfig struct {
-	Host     string     `mapstructure:"host"`
-	Port     string     `mapstructure:"port"`
-	User     string     `mapstructure:"user"`
-	Password string     `mapstructure:"password"`
-	DBName   string     `mapstructure:"dbname"`
-	SSLMode  string     `mapstructure:"sslmode"`
-	Pool     PoolConfig `mapstructure:"pool"`
+	Host      string     `mapstructure:"host"`
+	Port      string     `mapstructure:"port"`
+	User      string     `mapstructure:"user"`
+	Password  string     `mapstructure:"password"`
+	DBName    string     `mapstructure:"dbname"`
+	SSLMode   string     `mapstructure:"sslmode"`
+	Timescale bool       `mapstructure:"timescale"`
+	Pool      PoolConfig `mapstructure:"pool"`
 }
 
 type PoolConfig struct {
@@ -39,15 +48,74 @@ type PoolConfig struct {
 	ConnMaxIdleTime time.Duration `mapstrucutre:"conn_max_idle_time"`
 }
 
+// BatchConfig controls how many metrics are written to Postgres at once. A
+// batch is flushed when it is full or its oldest metric has waited for
+// FlushInterval, whichever comes first.
+type BatchConfig struct {
+	Size          int           `mapstructure:"size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// RetryConfig is the backoff applied to messages that fail to be handled.
+// After MaxAttempts they are sent to the dead-letter topic.
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Multiplier     float64       `mapstructure:"multiplier"`
+}
+
+// PartitioningConfig controls the partitions of the metrics table. Each one
+// covers an Interval of "daily" or "weekly" and Premake of them are kept
+// ahead of the current one; metrics collected further in the future are
+// rejected. Partitions are checked every CheckInterval.
+type PartitioningConfig struct {
+	Interval      string        `mapstructure:"interval"`
+	Premake       int           `mapstructure:"premake"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+}
+
+// RetentionConfig sets for how many days metrics are kept; a partition is
+// dropped once its whole period is older than that. Sources listed in
+// Sources are partitioned separately, starting with the periods created
+// after they are added. DefaultDays of 0 keeps everything else forever.
+type RetentionConfig struct {
+	DefaultDays int                     `mapstructure:"default_days"`
+	Sources     []SourceRetentionConfig `mapstructure:"sources"`
+}
+
+type SourceRetentionConfig struct {
+	Source string `mapstructure:"source"`
+	Days   int    `mapstructure:"days"`
+}
+
 type BrokerConfig struct {
-	Type  string      `mapstructure:"type"`
-	Kafka KafkaConfig `mapstructure:"kafka"`
+	Type     string         `mapstructure:"type"`
+	Kafka    KafkaConfig    `mapstructure:"kafka"`
+	NATS     NATSConfig     `mapstructure:"nats"`
+	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
 }
 
 type KafkaConfig struct {
-	Brokers []string `mapstructure:"brokers"`
-	Topic   string   `mapstructure:"topic"`
-	GroupID string   `mapstructure:"group_id"`
+	Brokers         []string `mapstructure:"brokers"`
+	Topic           string   `mapstructure:"topic"`
+	GroupID         string   `mapstructure:"group_id"`
+	DeadLetterTopic string   `mapstructure:"dead_letter_topic"`
+}
+
+type NATSConfig struct {
+	URL     string `mapstructure:"url"`
+	Stream  string `mapstructure:"stream"`
+	Subject string `mapstructure:"subject"`
+	Durable string `mapstructure:"durable"`
+}
+
+type RabbitMQConfig struct {
+	URL        string `mapstructure:"url"`
+	Exchange   string `mapstructure:"exchange"`
+	RoutingKey string `mapstructure:"routing_key"`
+	Queue      string `mapstructure:"queue"`
+	Prefetch   int    `mapstructure:"prefetch"`
 }
 
 func LoadConfig(path string) (*Config, error) {
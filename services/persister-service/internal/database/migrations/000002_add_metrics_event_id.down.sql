@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_metrics_event_id_collected_at;
+
+ALTER TABLE metrics DROP COLUMN IF EXISTS event_id;
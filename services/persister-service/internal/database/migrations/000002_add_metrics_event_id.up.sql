@@ -0,0 +1,4 @@
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS event_id VARCHAR(32);
+
+-- Rows stored before event IDs existed keep a NULL ID and never conflict.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metrics_event_id_collected_at ON metrics(event_id, collected_at);
@@ -0,0 +1,14 @@
+ALTER SEQUENCE metrics_id_seq OWNED BY NONE;
+
+CREATE TABLE metrics_plain (LIKE metrics INCLUDING DEFAULTS);
+INSERT INTO metrics_plain SELECT * FROM metrics;
+
+DROP TABLE metrics;
+DROP TABLE metric_partitions;
+
+ALTER TABLE metrics_plain RENAME TO metrics;
+ALTER TABLE metrics ADD PRIMARY KEY (id);
+ALTER SEQUENCE metrics_id_seq OWNED BY metrics.id;
+
+CREATE INDEX IF NOT EXISTS idx_metrics_source_name_collected_at ON metrics(source, name, collected_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metrics_event_id_collected_at ON metrics(event_id, collected_at);
@@ -0,0 +1,46 @@
+-- metrics becomes a table partitioned by collected_at, with every period
+-- split further by source so retention can be applied per source. The
+-- persister creates the period partitions ahead of time; the existing table
+-- is kept as a single partition holding everything before the first period.
+-- Its primary key and event ID index do not cover the partition keys, so
+-- they are dropped: attaching builds the parent's versions of both on it.
+ALTER TABLE metrics RENAME TO metrics_legacy;
+ALTER TABLE metrics_legacy DROP CONSTRAINT metrics_pkey;
+DROP INDEX idx_metrics_event_id_collected_at;
+ALTER INDEX idx_metrics_source_name_collected_at RENAME TO idx_metrics_legacy_source_name_collected_at;
+
+CREATE TABLE metrics (
+    id BIGINT NOT NULL DEFAULT nextval('metrics_id_seq'),
+    event_id VARCHAR(32),
+    source VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    labels JSONB,
+    collected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, collected_at, source)
+) PARTITION BY RANGE (collected_at);
+
+ALTER SEQUENCE metrics_id_seq OWNED BY metrics.id;
+
+CREATE INDEX idx_metrics_source_name_collected_at ON metrics(source, name, collected_at DESC);
+CREATE UNIQUE INDEX idx_metrics_event_id_collected_at ON metrics(event_id, collected_at, source);
+
+-- metric_partitions records the bounds of every partition the persister
+-- manages. parent is NULL for period partitions, source is NULL for the
+-- partition holding the sources without their own retention.
+CREATE TABLE metric_partitions (
+    name TEXT PRIMARY KEY,
+    parent TEXT REFERENCES metric_partitions(name) ON DELETE CASCADE,
+    source VARCHAR(255),
+    range_start TIMESTAMPTZ,
+    range_end TIMESTAMPTZ NOT NULL
+);
+
+DO $$
+DECLARE
+    boundary TIMESTAMPTZ := date_trunc('day', now() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' + INTERVAL '1 day';
+BEGIN
+    EXECUTE format('ALTER TABLE metrics ATTACH PARTITION metrics_legacy FOR VALUES FROM (MINVALUE) TO (%L)', boundary);
+
+    INSERT INTO metric_partitions (name, range_end) VALUES ('metrics_legacy', boundary);
+END $$;
@@ -0,0 +1,13 @@
+ALTER TABLE metrics ADD COLUMN name VARCHAR(255), ADD COLUMN labels JSONB;
+
+UPDATE metrics
+SET name = series.name, labels = series.labels
+FROM series
+WHERE series.id = metrics.series_id;
+
+ALTER TABLE metrics ALTER COLUMN name SET NOT NULL;
+ALTER TABLE metrics DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS series;
+
+CREATE INDEX IF NOT EXISTS idx_metrics_source_name_collected_at ON metrics(source, name, collected_at DESC);
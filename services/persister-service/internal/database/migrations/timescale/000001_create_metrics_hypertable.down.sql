@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS metrics;
+DROP SEQUENCE IF EXISTS metrics_id_seq;
@@ -0,0 +1,78 @@
+CREATE EXTENSION IF NOT EXISTS timescaledb;
+
+-- A database used without timescale holds metrics in a natively partitioned
+-- table, which cannot become a hypertable. It is moved aside here and its
+-- rows are copied into the hypertable below.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_class WHERE oid = to_regclass('metrics') AND relkind = 'p') THEN
+        ALTER TABLE metrics RENAME TO metrics_partitioned;
+        ALTER TABLE metrics_partitioned RENAME CONSTRAINT metrics_pkey TO metrics_partitioned_pkey;
+        ALTER INDEX IF EXISTS idx_metrics_source_name_collected_at RENAME TO idx_metrics_partitioned_source_name_collected_at;
+        ALTER INDEX idx_metrics_event_id_collected_at RENAME TO idx_metrics_partitioned_event_id_collected_at;
+        ALTER SEQUENCE metrics_id_seq OWNED BY NONE;
+    END IF;
+END $$;
+
+CREATE SEQUENCE IF NOT EXISTS metrics_id_seq;
+
+CREATE TABLE IF NOT EXISTS metrics (
+    id BIGINT NOT NULL DEFAULT nextval('metrics_id_seq'),
+    event_id VARCHAR(32),
+    source VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    labels JSONB,
+    collected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, collected_at)
+);
+
+ALTER SEQUENCE metrics_id_seq OWNED BY metrics.id;
+
+-- Unique indexes of a hypertable must include the time column, which the
+-- primary key of a table created before partitioning does not.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM pg_index
+        WHERE indrelid = 'metrics'::regclass AND indisprimary AND indnatts = 1
+    ) THEN
+        ALTER TABLE metrics DROP CONSTRAINT metrics_pkey;
+        ALTER TABLE metrics ADD PRIMARY KEY (id, collected_at);
+    END IF;
+END $$;
+
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS event_id VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_source_name_collected_at ON metrics(source, name, collected_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metrics_event_id_collected_at ON metrics(event_id, collected_at);
+
+SELECT create_hypertable('metrics', 'collected_at',
+    chunk_time_interval => INTERVAL '1 day',
+    if_not_exists => TRUE,
+    migrate_data => TRUE
+);
+
+DO $$
+BEGIN
+    IF to_regclass('metrics_partitioned') IS NULL THEN
+        RETURN;
+    END IF;
+
+    -- Names and labels live in the series catalog once it exists; 000007
+    -- moves them there again.
+    IF to_regclass('series') IS NOT NULL THEN
+        INSERT INTO metrics (id, event_id, source, name, value, labels, collected_at)
+        SELECT m.id, m.event_id, m.source, s.name, m.value, s.labels, m.collected_at
+        FROM metrics_partitioned m
+        JOIN series s ON s.id = m.series_id
+        ON CONFLICT DO NOTHING;
+    ELSE
+        INSERT INTO metrics (id, event_id, source, name, value, labels, collected_at)
+        SELECT id, event_id, source, name, value, labels, collected_at FROM metrics_partitioned
+        ON CONFLICT DO NOTHING;
+    END IF;
+
+    DROP TABLE metrics_partitioned;
+    DROP TABLE IF EXISTS metric_partitions;
+END $$;
@@ -0,0 +1,5 @@
+SELECT remove_compression_policy('metrics', if_exists => TRUE);
+
+SELECT decompress_chunk(chunk, if_compressed => TRUE) FROM show_chunks('metrics') AS chunk;
+
+ALTER TABLE metrics SET (timescaledb.compress = FALSE);
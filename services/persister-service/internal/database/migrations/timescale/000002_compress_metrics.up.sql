@@ -0,0 +1,9 @@
+-- Chunks are compressed once a week old. Rows of one series end up next to
+-- each other, which is what makes them compress well.
+ALTER TABLE metrics SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'source, name',
+    timescaledb.compress_orderby = 'collected_at DESC'
+);
+
+SELECT add_compression_policy('metrics', INTERVAL '7 days', if_not_exists => TRUE);
@@ -0,0 +1 @@
+DROP MATERIALIZED VIEW IF EXISTS metrics_1h;
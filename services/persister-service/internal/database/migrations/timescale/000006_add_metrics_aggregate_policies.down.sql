@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_metrics_1d_source_name_bucket;
+DROP INDEX IF EXISTS idx_metrics_1h_source_name_bucket;
+DROP INDEX IF EXISTS idx_metrics_1m_source_name_bucket;
+
+SELECT remove_continuous_aggregate_policy('metrics_1d', if_exists => TRUE);
+SELECT remove_continuous_aggregate_policy('metrics_1h', if_exists => TRUE);
+SELECT remove_continuous_aggregate_policy('metrics_1m', if_exists => TRUE);
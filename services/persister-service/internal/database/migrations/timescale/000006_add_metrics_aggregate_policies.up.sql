@@ -0,0 +1,27 @@
+-- Each aggregate refreshes the last few of its buckets; the newest ones are
+-- computed from raw rows at query time. Raw rows must be kept longer than the
+-- largest start_offset, or a refresh would wipe the buckets they fed.
+SELECT add_continuous_aggregate_policy('metrics_1m',
+    start_offset => INTERVAL '1 hour',
+    end_offset => INTERVAL '1 minute',
+    schedule_interval => INTERVAL '1 minute',
+    if_not_exists => TRUE
+);
+
+SELECT add_continuous_aggregate_policy('metrics_1h',
+    start_offset => INTERVAL '1 day',
+    end_offset => INTERVAL '1 hour',
+    schedule_interval => INTERVAL '30 minutes',
+    if_not_exists => TRUE
+);
+
+SELECT add_continuous_aggregate_policy('metrics_1d',
+    start_offset => INTERVAL '7 days',
+    end_offset => INTERVAL '1 day',
+    schedule_interval => INTERVAL '1 hour',
+    if_not_exists => TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_1m_source_name_bucket ON metrics_1m(source, name, bucket DESC);
+CREATE INDEX IF NOT EXISTS idx_metrics_1h_source_name_bucket ON metrics_1h(source, name, bucket DESC);
+CREATE INDEX IF NOT EXISTS idx_metrics_1d_source_name_bucket ON metrics_1d(source, name, bucket DESC);
@@ -0,0 +1,27 @@
+-- The aggregates over the old columns are not recreated here; migrate down
+-- past 000003 and up again to get them back.
+SELECT remove_compression_policy('metrics', if_exists => TRUE);
+SELECT decompress_chunk(chunk, if_compressed => TRUE) FROM show_chunks('metrics') AS chunk;
+ALTER TABLE metrics SET (timescaledb.compress = FALSE);
+
+ALTER TABLE metrics ADD COLUMN name VARCHAR(255), ADD COLUMN labels JSONB;
+
+UPDATE metrics
+SET name = series.name, labels = series.labels
+FROM series
+WHERE series.id = metrics.series_id;
+
+ALTER TABLE metrics ALTER COLUMN name SET NOT NULL;
+ALTER TABLE metrics DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS series;
+
+CREATE INDEX IF NOT EXISTS idx_metrics_source_name_collected_at ON metrics(source, name, collected_at DESC);
+
+ALTER TABLE metrics SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'source, name',
+    timescaledb.compress_orderby = 'collected_at DESC'
+);
+
+SELECT add_compression_policy('metrics', INTERVAL '7 days', if_not_exists => TRUE);
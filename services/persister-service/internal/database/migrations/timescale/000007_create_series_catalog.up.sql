@@ -0,0 +1,59 @@
+-- series is the catalog of every series ever stored. Samples reference it by
+-- ID instead of repeating the name and labels in every row. The continuous
+-- aggregates and compression settings refer to the dropped columns, so they
+-- are removed here and recreated by the following migrations; all chunks are
+-- decompressed and rewritten once.
+
+-- Dropping an aggregate removes its refresh policy as well.
+DROP MATERIALIZED VIEW IF EXISTS metrics_1m;
+DROP MATERIALIZED VIEW IF EXISTS metrics_1h;
+DROP MATERIALIZED VIEW IF EXISTS metrics_1d;
+
+SELECT remove_compression_policy('metrics', if_exists => TRUE);
+SELECT decompress_chunk(chunk, if_compressed => TRUE) FROM show_chunks('metrics') AS chunk;
+ALTER TABLE metrics SET (timescaledb.compress = FALSE);
+
+CREATE TABLE IF NOT EXISTS series (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    labels JSONB NOT NULL DEFAULT '{}',
+    unit TEXT,
+    description TEXT,
+    first_seen TIMESTAMPTZ NOT NULL,
+    last_seen TIMESTAMPTZ NOT NULL,
+    UNIQUE (source, name, labels)
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_name ON series(name);
+CREATE INDEX IF NOT EXISTS idx_series_labels ON series USING GIN (labels);
+
+INSERT INTO series (source, name, labels, first_seen, last_seen)
+SELECT source, name, COALESCE(NULLIF(labels, 'null'), '{}'), min(collected_at), max(collected_at)
+FROM metrics
+GROUP BY 1, 2, 3
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE metrics ADD COLUMN series_id BIGINT;
+
+UPDATE metrics
+SET series_id = series.id
+FROM series
+WHERE series.source = metrics.source
+    AND series.name = metrics.name
+    AND series.labels = COALESCE(NULLIF(metrics.labels, 'null'), '{}');
+
+ALTER TABLE metrics ALTER COLUMN series_id SET NOT NULL;
+ALTER TABLE metrics ADD CONSTRAINT metrics_series_id_fkey FOREIGN KEY (series_id) REFERENCES series(id);
+
+ALTER TABLE metrics DROP COLUMN name, DROP COLUMN labels;
+
+CREATE INDEX IF NOT EXISTS idx_metrics_series_id_collected_at ON metrics(series_id, collected_at DESC);
+
+ALTER TABLE metrics SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'series_id',
+    timescaledb.compress_orderby = 'collected_at DESC'
+);
+
+SELECT add_compression_policy('metrics', INTERVAL '7 days', if_not_exists => TRUE);
@@ -0,0 +1,12 @@
+CREATE MATERIALIZED VIEW IF NOT EXISTS metrics_1h
+WITH (timescaledb.continuous, timescaledb.materialized_only = FALSE) AS
+SELECT
+    time_bucket(INTERVAL '1 hour', collected_at) AS bucket,
+    series_id,
+    avg(value) AS avg_value,
+    min(value) AS min_value,
+    max(value) AS max_value,
+    count(*) AS sample_count
+FROM metrics
+GROUP BY bucket, series_id
+WITH NO DATA;
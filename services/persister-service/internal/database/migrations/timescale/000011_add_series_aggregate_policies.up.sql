@@ -0,0 +1,25 @@
+-- The same refresh windows as the aggregates these replace, see 000006.
+SELECT add_continuous_aggregate_policy('metrics_1m',
+    start_offset => INTERVAL '1 hour',
+    end_offset => INTERVAL '1 minute',
+    schedule_interval => INTERVAL '1 minute',
+    if_not_exists => TRUE
+);
+
+SELECT add_continuous_aggregate_policy('metrics_1h',
+    start_offset => INTERVAL '1 day',
+    end_offset => INTERVAL '1 hour',
+    schedule_interval => INTERVAL '30 minutes',
+    if_not_exists => TRUE
+);
+
+SELECT add_continuous_aggregate_policy('metrics_1d',
+    start_offset => INTERVAL '7 days',
+    end_offset => INTERVAL '1 day',
+    schedule_interval => INTERVAL '1 hour',
+    if_not_exists => TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_1m_series_id_bucket ON metrics_1m(series_id, bucket DESC);
+CREATE INDEX IF NOT EXISTS idx_metrics_1h_series_id_bucket ON metrics_1h(series_id, bucket DESC);
+CREATE INDEX IF NOT EXISTS idx_metrics_1d_series_id_bucket ON metrics_1d(series_id, bucket DESC);
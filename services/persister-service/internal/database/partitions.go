@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ repository.PartitionRepository = (*Storage)(nil)
+
+func (s *Storage) ListPartitions(ctx context.Context) ([]repository.Partition, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, COALESCE(parent, ''), COALESCE(source, ''), range_start, range_end
+		FROM metric_partitions
+		ORDER BY range_end, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []repository.Partition
+	for rows.Next() {
+		var p repository.Partition
+		var start sql.NullTime
+		if err := rows.Scan(&p.Name, &p.Parent, &p.Source, &start, &p.End); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+		p.Start = start.Time
+
+		partitions = append(partitions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	return partitions, nil
+}
+
+// CreatePeriodPartition creates the period and its default partition in one
+// transaction, so a period never exists without somewhere to put its rows.
+func (s *Storage) CreatePeriodPartition(ctx context.Context, period, def repository.Partition) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Partition bounds cannot be bind parameters, the statements are built
+	// from generated names and formatted timestamps only.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE %s PARTITION OF metrics FOR VALUES FROM (%s) TO (%s) PARTITION BY LIST (source)",
+		pgx.Identifier{period.Name}.Sanitize(),
+		quoteLiteral(period.Start.UTC().Format(time.RFC3339)),
+		quoteLiteral(period.End.UTC().Format(time.RFC3339)),
+	)); err != nil {
+		return fmt.Errorf("failed to create partition %s: %w", period.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE %s PARTITION OF %s DEFAULT",
+		pgx.Identifier{def.Name}.Sanitize(),
+		pgx.Identifier{period.Name}.Sanitize(),
+	)); err != nil {
+		return fmt.Errorf("failed to create partition %s: %w", def.Name, err)
+	}
+
+	if err := recordPartition(ctx, tx, period); err != nil {
+		return err
+	}
+	if err := recordPartition(ctx, tx, def); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit partition %s: %w", period.Name, err)
+	}
+
+	return nil
+}
+
+// CreateSourcePartition fails if the period's default partition already
+// holds rows of the source, which is only the case for periods that have
+// started.
+func (s *Storage) CreateSourcePartition(ctx context.Context, p repository.Partition) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE %s PARTITION OF %s FOR VALUES IN (%s)",
+		pgx.Identifier{p.Name}.Sanitize(),
+		pgx.Identifier{p.Parent}.Sanitize(),
+		quoteLiteral(p.Source),
+	)); err != nil {
+		return fmt.Errorf("failed to create partition %s: %w", p.Name, err)
+	}
+
+	if err := recordPartition(ctx, tx, p); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit partition %s: %w", p.Name, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DropPartition(ctx context.Context, p repository.Partition) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+pgx.Identifier{p.Name}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to drop partition %s: %w", p.Name, err)
+	}
+
+	// Partitions of a dropped period are removed by the foreign key cascade.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM metric_partitions WHERE name = $1", p.Name); err != nil {
+		return fmt.Errorf("failed to forget partition %s: %w", p.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dropping partition %s: %w", p.Name, err)
+	}
+
+	return nil
+}
+
+func recordPartition(ctx context.Context, tx *sql.Tx, p repository.Partition) error {
+	start := sql.NullTime{Time: p.Start, Valid: !p.Start.IsZero()}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO metric_partitions (name, parent, source, range_start, range_end)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5)
+	`, p.Name, p.Parent, p.Source, start, p.End); err != nil {
+		return fmt.Errorf("failed to record partition %s: %w", p.Name, err)
+	}
+
+	return nil
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
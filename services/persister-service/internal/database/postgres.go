@@ -7,25 +7,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/config"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/metrics"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/repository"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/models"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/event"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
-
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
-//go:embed migrations/*.sql
+//go:embed migrations/*.sql migrations/timescale/*.sql
 var migrationsFS embed.FS
 
 type Storage struct {
-	db      *sql.DB
-	metrics *metrics.Metrics
+	db        *sql.DB
+	metrics   *metrics.Metrics
+	timescale bool
 }
 
 var _ repository.MetricRepository = (*Storage)(nil)
@@ -53,16 +57,24 @@ func New(cfg config.PostgresConfig, metrics *metrics.Metrics) (*Storage, error)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Storage{db: db, metrics: metrics}, nil
+	return &Storage{db: db, metrics: metrics, timescale: cfg.Timescale}, nil
 }
 
+// RunMigrations applies the migrations of the configured storage mode. The
+// TimescaleDB migrations are versioned in a table of their own, as they
+// convert whatever schema the plain ones left behind.
 func (s *Storage) RunMigrations() error {
-	sourceInstance, err := iofs.New(migrationsFS, "migrations")
+	dir, table := "migrations", ""
+	if s.timescale {
+		dir, table = "migrations/timescale", "schema_migrations_timescale"
+	}
+
+	sourceInstance, err := iofs.New(migrationsFS, dir)
 	if err != nil {
 		return fmt.Errorf("could not create source instance from embedded fs: %w", err)
 	}
 
-	driver, err := postgres.WithInstance(s.db, &postgres.Config{})
+	driver, err := postgres.WithInstance(s.db, &postgres.Config{MigrationsTable: table})
 	if err != nil {
 		return fmt.Errorf("could not create postgres driver for migration: %w", err)
 	}
@@ -83,63 +95,192 @@ func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
+// metricColumns are the columns staged for every metric, in row order.
+var metricColumns = []string{"event_id", "source", "name", "value", "labels", "collected_at", "unit", "description"}
+
+// upsertSeries adds the series of the staged metrics to the catalog. A known
+// series is only rewritten when it gains metadata or its time range grows by
+// more than a minute, so last_seen may lag by up to a minute instead of every
+// batch updating every series it touches. Rows are locked in a fixed order so
+// concurrent batches do not deadlock.
+const upsertSeries = `
+	INSERT INTO series (source, name, labels, unit, description, first_seen, last_seen)
+	SELECT source, name, labels, max(NULLIF(unit, '')), max(NULLIF(description, '')), min(collected_at), max(collected_at)
+	FROM metrics_staging
+	GROUP BY source, name, labels
+	ORDER BY source, name, labels
+	ON CONFLICT (source, name, labels) DO UPDATE SET
+		unit = COALESCE(EXCLUDED.unit, series.unit),
+		description = COALESCE(EXCLUDED.description, series.description),
+		first_seen = LEAST(series.first_seen, EXCLUDED.first_seen),
+		last_seen = GREATEST(series.last_seen, EXCLUDED.last_seen)
+	WHERE EXCLUDED.first_seen < series.first_seen
+		OR EXCLUDED.last_seen > series.last_seen + INTERVAL '1 minute'
+		OR (EXCLUDED.unit IS NOT NULL AND EXCLUDED.unit IS DISTINCT FROM series.unit)
+		OR (EXCLUDED.description IS NOT NULL AND EXCLUDED.description IS DISTINCT FROM series.description)
+`
+
+// StoreBranch writes metrics with a single COPY. Metrics whose event ID is
+// already stored are skipped and counted as deduplicated. If the database
+// rejects the copy because of a bad row, the metrics are stored one by one
+// instead, so the valid ones are still stored and the rest are reported in a
+// BatchInsertError.
 func (s *Storage) StoreBranch(ctx context.Context, metrics []models.Metric) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
+	var batchErr repository.BatchInsertError
+
+	rows := make([][]any, 0, len(metrics))
+	positions := make([]int, 0, len(metrics))
+	for i, metric := range metrics {
+		labelsJSON := []byte("{}")
+		if metric.Labels != nil {
+			var err error
+			labelsJSON, err = json.Marshal(metric.Labels)
+			if err != nil {
+				batchErr.FailedCount++
+				batchErr.Failed = append(batchErr.Failed, i)
+				batchErr.Errors = append(batchErr.Errors, fmt.Errorf("metric %s/%s: failed to marshal labels: %w",
+					metric.Source,
+					metric.Name,
+					err,
+				))
+				continue
+			}
+		}
+
+		eventID := metric.EventID
+		if eventID == "" {
+			eventID = event.ID(metric.Source, metric.Name, metric.Labels, metric.CollectedAt)
+		}
+
+		rows = append(rows, []any{eventID, metric.Source, metric.Name, metric.Value, labelsJSON, metric.CollectedAt, metric.Unit, metric.Description})
+		positions = append(positions, i)
+	}
+
+	var inserted int64
+	if len(rows) > 0 {
+		var err error
+		inserted, err = s.copyRows(ctx, rows)
+		switch {
+		case err == nil:
+			batchErr.SuccessfullCount += len(rows)
+		case isDataError(err):
+			inserted = s.insertRows(ctx, rows, positions, &batchErr)
+		default:
+			s.metrics.DatabaseErrorsTotal.Inc()
+			return fmt.Errorf("failed to copy metrics: %w", err)
+		}
+	}
+
+	s.metrics.MetricsSavedTotal.Add(float64(inserted))
+	s.metrics.MetricsDeduplicatedTotal.Add(float64(int64(batchErr.SuccessfullCount) - inserted))
+
+	if len(batchErr.Errors) > 0 {
 		s.metrics.DatabaseErrorsTotal.Inc()
-		return fmt.Errorf("could not begin transaction: %w", err)
+		return &batchErr
 	}
-	defer tx.Rollback()
 
-	statement, err := tx.PrepareContext(ctx, `
-		INSERT INTO metrics (source, name, value, labels, collected_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`)
+	return nil
+}
+
+// copyRows streams rows into a staging table over the native pgx connection,
+// as database/sql has no COPY support, then adds their series to the catalog
+// and moves the samples into metrics in the same transaction. COPY cannot
+// skip conflicting rows itself. Either every row is stored or none is; the
+// number of rows that were not duplicates is returned.
+func (s *Storage) copyRows(ctx context.Context, rows [][]any) (int64, error) {
+	conn, err := s.db.Conn(ctx)
 	if err != nil {
-		s.metrics.DatabaseErrorsTotal.Inc()
-		return fmt.Errorf("could not prepare transaction: %w", err)
+		return 0, fmt.Errorf("could not acquire connection: %w", err)
 	}
-	defer statement.Close()
+	defer conn.Close()
 
-	var batchErr repository.BatchInsertError
+	var inserted int64
+	err = conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
 
-	for _, metric := range metrics {
-		labelsJSON, err := json.Marshal(metric.Labels)
+		tx, err := pgxConn.Begin(ctx)
 		if err != nil {
-			batchErr.FailedCount++
-			batchErr.Errors = append(batchErr.Errors, fmt.Errorf("metric %s/%s: failed to marshal labels: %w",
-				metric.Source,
-				metric.Name,
-				err,
-			))
-			continue
+			return err
 		}
+		defer tx.Rollback(ctx)
 
-		_, err = statement.Exec(metric.Source, metric.Name, metric.Value, labelsJSON, metric.CollectedAt)
+		if _, err := tx.Exec(ctx, `
+			CREATE TEMP TABLE metrics_staging (
+				event_id VARCHAR(32),
+				source VARCHAR(255),
+				name VARCHAR(255),
+				value DOUBLE PRECISION,
+				labels JSONB,
+				collected_at TIMESTAMPTZ,
+				unit TEXT,
+				description TEXT
+			) ON COMMIT DROP
+		`); err != nil {
+			return err
+		}
+
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"metrics_staging"}, metricColumns, pgx.CopyFromRows(rows)); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, upsertSeries); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO metrics (event_id, series_id, source, value, collected_at)
+			SELECT staging.event_id, series.id, staging.source, staging.value, staging.collected_at
+			FROM metrics_staging staging
+			JOIN series
+				ON series.source = staging.source
+				AND series.name = staging.name
+				AND series.labels = staging.labels
+			ON CONFLICT DO NOTHING
+		`)
+		if err != nil {
+			return err
+		}
+		inserted = tag.RowsAffected()
+
+		return tx.Commit(ctx)
+	})
+
+	return inserted, err
+}
+
+// insertRows stores rows one at a time and returns how many were not
+// duplicates.
+func (s *Storage) insertRows(ctx context.Context, rows [][]any, positions []int, batchErr *repository.BatchInsertError) int64 {
+	var inserted int64
+
+	for i, row := range rows {
+		n, err := s.copyRows(ctx, [][]any{row})
 		if err != nil {
 			batchErr.FailedCount++
+			batchErr.Failed = append(batchErr.Failed, positions[i])
 			batchErr.Errors = append(batchErr.Errors, fmt.Errorf("metric %s/%s: db insert failed: %w",
-				metric.Source,
-				metric.Name,
+				row[1],
+				row[2],
 				err,
 			))
 			continue
 		}
 
 		batchErr.SuccessfullCount++
+		inserted += n
 	}
 
-	if err := tx.Commit(); err != nil {
-		s.metrics.DatabaseErrorsTotal.Inc()
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+	return inserted
+}
 
-	if len(batchErr.Errors) > 0 {
-		s.metrics.DatabaseErrorsTotal.Inc()
-		return &batchErr
+// isDataError reports whether Postgres rejected the data itself, as opposed
+// to a connection or server failure worth retrying. Class 22 covers data
+// exceptions and class 23 constraint violations.
+func isDataError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
 	}
 
-	s.metrics.MetricsSavedTotal.Inc()
-
-	return nil
+	return strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")
 }
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// minTimescaleRetention keeps raw metrics longer than the daily aggregate
+// refreshes them for, so a refresh never finds its raw rows dropped and
+// empties the buckets.
+const minTimescaleRetention = 8 * 24 * time.Hour
+
+// SetRetentionPolicy makes TimescaleDB drop chunks of metrics older than
+// retention, replacing the previous policy. A zero retention keeps metrics
+// forever. Retentions too short for the continuous aggregates are raised to
+// the minimum; the retention applied is returned.
+func (s *Storage) SetRetentionPolicy(ctx context.Context, retention time.Duration) (time.Duration, error) {
+	if retention > 0 && retention < minTimescaleRetention {
+		retention = minTimescaleRetention
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT remove_retention_policy('metrics', if_exists => TRUE)`); err != nil {
+		return 0, fmt.Errorf("failed to remove retention policy: %w", err)
+	}
+
+	if retention > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`SELECT add_retention_policy('metrics', make_interval(secs => $1))`,
+			retention.Seconds(),
+		); err != nil {
+			return 0, fmt.Errorf("failed to add retention policy: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit retention policy: %w", err)
+	}
+
+	return retention, nil
+}
@@ -6,9 +6,20 @@ import (
 )
 
 type Metrics struct {
-	MetricsConsumedTotal prometheus.Counter
-	MetricsSavedTotal    prometheus.Counter
-	DatabaseErrorsTotal  prometheus.Counter
+	MetricsConsumedTotal     prometheus.Counter
+	MetricsSavedTotal        prometheus.Counter
+	MetricsDeduplicatedTotal prometheus.Counter
+	DatabaseErrorsTotal      prometheus.Counter
+	CommitErrorsTotal        prometheus.Counter
+	BatchSize                prometheus.Histogram
+	BatchFlushDuration       prometheus.Histogram
+	DeadLettersTotal         *prometheus.CounterVec
+
+	PartitionsCreatedTotal      prometheus.Counter
+	PartitionsDroppedTotal      prometheus.Counter
+	PartitionErrorsTotal        prometheus.Counter
+	Partitions                  prometheus.Gauge
+	PartitionMaintenanceSuccess prometheus.Gauge
 }
 
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -22,9 +33,51 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "persister_service_metrics_saved_total",
 			Help: "Total number of metrics successfully saved to the database",
 		}),
+		MetricsDeduplicatedTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "persister_service_metrics_deduplicated_total",
+			Help: "Total number of metrics skipped because their event was already stored",
+		}),
 		DatabaseErrorsTotal: factory.NewCounter(prometheus.CounterOpts{
 			Name: "persister_service_database_errors_total",
 			Help: "Total number of database write errors",
 		}),
+		CommitErrorsTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "persister_service_commit_errors_total",
+			Help: "Total number of failed message commits after a stored batch",
+		}),
+		BatchSize: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "persister_service_batch_size",
+			Help:    "Number of metrics in each flushed batch",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+		}),
+		BatchFlushDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "persister_service_batch_flush_duration_seconds",
+			Help:    "Time taken to store a batch, including retries",
+			Buckets: prometheus.DefBuckets,
+		}),
+		DeadLettersTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "persister_service_dead_letters_total",
+			Help: "Total number of messages given up on and sent to the dead-letter queue",
+		}, []string{"reason"}),
+		PartitionsCreatedTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "persister_service_partitions_created_total",
+			Help: "Total number of metrics table partitions created",
+		}),
+		PartitionsDroppedTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "persister_service_partitions_dropped_total",
+			Help: "Total number of metrics table partitions dropped after their retention",
+		}),
+		PartitionErrorsTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "persister_service_partition_errors_total",
+			Help: "Total number of failed partition creations, drops and listings",
+		}),
+		Partitions: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "persister_service_partitions",
+			Help: "Number of metrics table partitions after the last maintenance",
+		}),
+		PartitionMaintenanceSuccess: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "persister_service_partition_maintenance_last_success_timestamp_seconds",
+			Help: "Unix time of the last partition maintenance that completed without errors",
+		}),
 	}
 }
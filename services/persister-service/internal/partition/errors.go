@@ -0,0 +1,11 @@
+package partition
+
+import "fmt"
+
+type UnsupportedIntervalError struct {
+	Interval string
+}
+
+func (e UnsupportedIntervalError) Error() string {
+	return fmt.Sprintf("unsupported partition interval '%s', expected 'daily' or 'weekly'", e.Interval)
+}
@@ -0,0 +1,362 @@
+package partition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/repository"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/logger"
+)
+
+const (
+	defaultPremake       = 7
+	defaultCheckInterval = time.Hour
+
+	namePrefix = "metrics_p"
+	day        = 24 * time.Hour
+)
+
+// Manager keeps the partitions of the metrics table: it creates the upcoming
+// periods ahead of time and drops the ones past their retention. All periods
+// start and end at midnight UTC.
+type Manager struct {
+	repo    repository.PartitionRepository
+	log     logger.Logger
+	metrics *metrics.Metrics
+
+	weekly        bool
+	premake       int
+	checkInterval time.Duration
+
+	// A zero retention keeps partitions forever.
+	defaultRetention time.Duration
+	sourceRetention  map[string]time.Duration
+	longestRetention time.Duration
+}
+
+func NewManager(repo repository.PartitionRepository, log logger.Logger, metrics *metrics.Metrics, partCfg config.PartitioningConfig, retCfg config.RetentionConfig) (*Manager, error) {
+	m := &Manager{
+		repo:             repo,
+		log:              log,
+		metrics:          metrics,
+		premake:          partCfg.Premake,
+		checkInterval:    partCfg.CheckInterval,
+		defaultRetention: time.Duration(max(retCfg.DefaultDays, 0)) * day,
+		sourceRetention:  make(map[string]time.Duration, len(retCfg.Sources)),
+		longestRetention: LongestRetention(retCfg),
+	}
+
+	switch partCfg.Interval {
+	case "", "daily":
+	case "weekly":
+		m.weekly = true
+	default:
+		return nil, UnsupportedIntervalError{Interval: partCfg.Interval}
+	}
+
+	if m.premake <= 0 {
+		m.premake = defaultPremake
+	}
+	if m.checkInterval <= 0 {
+		m.checkInterval = defaultCheckInterval
+	}
+
+	for _, src := range retCfg.Sources {
+		m.sourceRetention[src.Source] = time.Duration(max(src.Days, 0)) * day
+	}
+
+	return m, nil
+}
+
+// Run maintains the partitions every check interval until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Maintain(ctx); err != nil && ctx.Err() == nil {
+				m.log.Error("partition maintenance failed", "error", err)
+			}
+		}
+	}
+}
+
+// Maintain creates the partitions up to premake periods ahead, including
+// periods missed while the persister was not running, and drops the expired
+// ones. A failed partition does not stop
+// the others from being handled.
+func (m *Manager) Maintain(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	partitions, err := m.repo.ListPartitions(ctx)
+	if err != nil {
+		m.metrics.PartitionErrorsTotal.Inc()
+		return err
+	}
+
+	created, createErrs := m.create(ctx, now, partitions)
+	dropped, dropErrs := m.drop(ctx, now, partitions)
+
+	m.metrics.Partitions.Set(float64(len(partitions) + created - dropped))
+
+	if err := errors.Join(append(createErrs, dropErrs...)...); err != nil {
+		return err
+	}
+
+	m.metrics.PartitionMaintenanceSuccess.SetToCurrentTime()
+	return nil
+}
+
+// create adds the missing periods and gives the periods that have not
+// started yet a partition for every source with its own retention. Sources
+// cannot be split out of a period that already has rows of them. It returns
+// the number of partitions created.
+func (m *Manager) create(ctx context.Context, now time.Time, partitions []repository.Partition) (int, []error) {
+	var periods []repository.Partition
+	sources := make(map[string]map[string]bool)
+	for _, p := range partitions {
+		switch {
+		case p.Parent == "":
+			periods = append(periods, p)
+		case p.Source != "":
+			if sources[p.Parent] == nil {
+				sources[p.Parent] = make(map[string]bool)
+			}
+			sources[p.Parent][p.Source] = true
+		}
+	}
+
+	var created int
+	var errs []error
+	fresh := make(map[string]bool)
+
+	last := m.periodStart(now)
+	for i := 0; i < m.premake; i++ {
+		last = m.periodEnd(last)
+	}
+
+	for start := m.firstMissingPeriod(now, periods); !start.After(last); {
+		end := m.periodEnd(start)
+
+		// Skip whatever part of the period existing partitions already
+		// cover, such as the legacy partition or periods created with a
+		// different interval.
+		from, to := start, end
+		for _, p := range periods {
+			if !p.End.After(from) || (!p.Start.IsZero() && !p.Start.Before(to)) {
+				continue
+			}
+
+			if p.Start.IsZero() || !p.Start.After(from) {
+				from = p.End
+			} else {
+				to = p.Start
+			}
+		}
+
+		if from.Before(to) {
+			period := repository.Partition{Name: namePrefix + from.Format("20060102"), Start: from, End: to}
+			def := repository.Partition{Name: period.Name + "_default", Parent: period.Name, Start: from, End: to}
+
+			if err := m.repo.CreatePeriodPartition(ctx, period, def); err != nil {
+				m.metrics.PartitionErrorsTotal.Inc()
+				m.log.Error("failed to create partition", "partition", period.Name, "error", err)
+				errs = append(errs, err)
+			} else {
+				m.metrics.PartitionsCreatedTotal.Add(2)
+				m.log.Info("partition created", "partition", period.Name, "from", from, "to", to)
+
+				created += 2
+				periods = append(periods, period)
+				fresh[period.Name] = true
+			}
+		}
+
+		start = end
+	}
+
+	for _, period := range periods {
+		if period.Start.IsZero() || (!period.Start.After(now) && !fresh[period.Name]) {
+			continue
+		}
+
+		for source := range m.sourceRetention {
+			if sources[period.Name][source] {
+				continue
+			}
+
+			p := repository.Partition{
+				Name:   sourcePartitionName(period.Name, source),
+				Parent: period.Name,
+				Source: source,
+				Start:  period.Start,
+				End:    period.End,
+			}
+			if err := m.repo.CreateSourcePartition(ctx, p); err != nil {
+				m.metrics.PartitionErrorsTotal.Inc()
+				m.log.Error("failed to create partition", "partition", p.Name, "source", source, "error", err)
+				errs = append(errs, err)
+				continue
+			}
+
+			m.metrics.PartitionsCreatedTotal.Inc()
+			m.log.Info("partition created", "partition", p.Name, "source", source)
+			created++
+		}
+	}
+
+	return created, errs
+}
+
+// firstMissingPeriod returns the start of the first period to create: the
+// current one, or an earlier one if the newest partition ends before it, so
+// metrics consumed late after downtime still have a partition. Periods past
+// every retention would be dropped right away and are not filled.
+func (m *Manager) firstMissingPeriod(now time.Time, periods []repository.Partition) time.Time {
+	current := m.periodStart(now)
+
+	var newest time.Time
+	for _, p := range periods {
+		if p.End.After(newest) {
+			newest = p.End
+		}
+	}
+	if newest.IsZero() || !newest.Before(current) {
+		return current
+	}
+
+	if m.longestRetention > 0 {
+		if oldest := now.Add(-m.longestRetention); newest.Before(oldest) {
+			newest = oldest
+		}
+	}
+
+	return m.periodStart(newest)
+}
+
+// drop removes the partitions whose period ended before their retention.
+// Periods outlive every source partition in them, so dropping a period only
+// removes partitions that are expired anyway. It returns the number of
+// partitions dropped.
+func (m *Manager) drop(ctx context.Context, now time.Time, partitions []repository.Partition) (int, []error) {
+	var dropped int
+	var errs []error
+	droppedPeriods := make(map[string]bool)
+
+	for _, periodsPass := range []bool{true, false} {
+		for _, p := range partitions {
+			if (p.Parent == "") != periodsPass || droppedPeriods[p.Parent] {
+				continue
+			}
+
+			retention := m.retention(p)
+			if retention == 0 || p.End.After(now.Add(-retention)) {
+				continue
+			}
+
+			if err := m.repo.DropPartition(ctx, p); err != nil {
+				m.metrics.PartitionErrorsTotal.Inc()
+				m.log.Error("failed to drop partition", "partition", p.Name, "error", err)
+				errs = append(errs, err)
+				continue
+			}
+
+			n := 1
+			if periodsPass {
+				droppedPeriods[p.Name] = true
+				for _, child := range partitions {
+					if child.Parent == p.Name {
+						n++
+					}
+				}
+			}
+
+			m.metrics.PartitionsDroppedTotal.Add(float64(n))
+			m.log.Info("partition dropped", "partition", p.Name, "end", p.End)
+			dropped += n
+		}
+	}
+
+	return dropped, errs
+}
+
+// retention returns how long the partition is kept, zero meaning forever. A
+// period is kept as long as the longest retention of the sources in it.
+func (m *Manager) retention(p repository.Partition) time.Duration {
+	switch {
+	case p.Source != "":
+		if retention, ok := m.sourceRetention[p.Source]; ok {
+			return retention
+		}
+		return m.defaultRetention
+	case p.Parent != "":
+		return m.defaultRetention
+	}
+
+	return m.longestRetention
+}
+
+// LongestRetention returns the longest time any metrics are kept for, zero
+// meaning some are kept forever.
+func LongestRetention(cfg config.RetentionConfig) time.Duration {
+	if cfg.DefaultDays <= 0 {
+		return 0
+	}
+
+	longest := cfg.DefaultDays
+	for _, src := range cfg.Sources {
+		if src.Days <= 0 {
+			return 0
+		}
+		longest = max(longest, src.Days)
+	}
+
+	return time.Duration(longest) * day
+}
+
+func (m *Manager) periodStart(t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if m.weekly {
+		// Weeks start on Monday.
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	}
+
+	return start
+}
+
+func (m *Manager) periodEnd(start time.Time) time.Time {
+	if m.weekly {
+		return start.AddDate(0, 0, 7)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
+// sourcePartitionName derives a table name from the source, which may contain
+// any characters. The hash keeps sources that differ only in those apart.
+func sourcePartitionName(period, source string) string {
+	slug := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(source))
+	if len(slug) > 20 {
+		slug = slug[:20]
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(source))
+
+	return fmt.Sprintf("%s_%s_%08x", period, slug, h.Sum32())
+}
@@ -2,60 +2,246 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/config"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/metrics"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/repository"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/consumer"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/logger"
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/models"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/shared/retry"
+)
+
+const (
+	defaultBatchSize     = 1000
+	defaultFlushInterval = time.Second
+
+	// shutdownFlushTimeout bounds the last flush after the consumer is stopped.
+	shutdownFlushTimeout = 10 * time.Second
 )
 
 type Consumer struct {
 	messageConsumer consumer.MessageConsumer
 	repo            repository.MetricRepository
+	dlq             consumer.DeadLetterQueue
 	log             logger.Logger
 	metrics         *metrics.Metrics
+
+	batchSize     int
+	flushInterval time.Duration
+	retry         retry.Policy
 }
 
-func NewConsumer(messageConsumer consumer.MessageConsumer, repo repository.MetricRepository, log logger.Logger, metrics *metrics.Metrics) *Consumer {
+// NewConsumer creates the batching consumer. dlq may be nil, in which case
+// messages that cannot be stored are logged and dropped.
+func NewConsumer(messageConsumer consumer.MessageConsumer, repo repository.MetricRepository, dlq consumer.DeadLetterQueue, log logger.Logger, metrics *metrics.Metrics, batchCfg config.BatchConfig, retryCfg config.RetryConfig) *Consumer {
+	if batchCfg.Size <= 0 {
+		batchCfg.Size = defaultBatchSize
+	}
+	if batchCfg.FlushInterval <= 0 {
+		batchCfg.FlushInterval = defaultFlushInterval
+	}
+
 	return &Consumer{
 		messageConsumer: messageConsumer,
 		repo:            repo,
+		dlq:             dlq,
 		log:             log,
 		metrics:         metrics,
+		batchSize:       batchCfg.Size,
+		flushInterval:   batchCfg.FlushInterval,
+		retry:           retry.NewPolicy(retryCfg.MaxAttempts, retryCfg.InitialBackoff, retryCfg.MaxBackoff, retryCfg.Multiplier),
 	}
 }
 
+// Start collects metrics into batches and stores a batch once it is full or
+// its oldest metric has waited for the flush interval. Messages are committed
+// only after their batch is stored or dead-lettered, so a crash redelivers
+// them instead of losing them.
 func (c *Consumer) Start(ctx context.Context) {
-	c.log.Info("starting consumer")
+	c.log.Info("starting consumer", "batch_size", c.batchSize, "flush_interval", c.flushInterval)
+
+	batch := make([]consumer.Message, 0, c.batchSize)
+	var deadline time.Time
+	var fetchFailures int
 
 	for {
-		select {
-		case <-ctx.Done():
-			c.log.Info("consumer stopped")
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if len(batch) > 0 {
+			fetchCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+
+		msg, err := c.messageConsumer.FetchMetric(fetchCtx)
+		cancel()
+
+		var decodeErr consumer.DecodeError
+		switch {
+		case ctx.Err() != nil:
+			c.shutdown(batch)
 			return
+		case errors.Is(err, context.DeadlineExceeded):
+			batch = c.flush(ctx, batch)
+		case errors.As(err, &decodeErr):
+			c.log.Error("failed to decode metric", "error", err)
+
+			letter := consumer.DeadLetter{
+				Key:         decodeErr.Key,
+				Payload:     decodeErr.Payload,
+				ContentType: decodeErr.ContentType,
+				Err:         err,
+				Attempts:    1,
+				FailedAt:    time.Now(),
+			}
+			if !c.deadLetter(ctx, "decode", letter) {
+				c.log.Info("consumer stopped")
+				return
+			}
+		case err != nil:
+			fetchFailures++
+			c.log.Error("failed to consume metric", "error", err)
+			c.retry.Wait(ctx, fetchFailures)
 		default:
-			metric, err := c.messageConsumer.ConsumeMetric(ctx)
-			if err != nil {
-				c.log.Error("failed to consume metric", "error", err)
-				time.Sleep(time.Second)
-				continue
+			fetchFailures = 0
+			c.metrics.MetricsConsumedTotal.Inc()
+
+			if len(batch) == 0 {
+				deadline = time.Now().Add(c.flushInterval)
 			}
 
-			c.metrics.MetricsConsumedTotal.Inc()
+			batch = append(batch, msg)
+			if len(batch) >= c.batchSize {
+				batch = c.flush(ctx, batch)
+			}
+		}
+	}
+}
+
+// flush stores the batch and commits the messages it came from. The batch is
+// returned emptied, or untouched if ctx was cancelled before it was stored.
+func (c *Consumer) flush(ctx context.Context, batch []consumer.Message) []consumer.Message {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if !c.store(ctx, batch) {
+		return batch
+	}
+
+	if err := c.messageConsumer.Commit(ctx); err != nil {
+		c.metrics.CommitErrorsTotal.Inc()
+		c.log.Error("failed to commit stored batch, messages will be redelivered", "error", err, "count", len(batch))
+	} else {
+		c.log.Debug("successfully stored batch", "count", len(batch))
+	}
+
+	return batch[:0]
+}
+
+// store writes the batch until every metric is durable or dead-lettered. A
+// database that cannot be reached is waited for indefinitely; metrics it
+// rejects are retried under the retry policy and then dead-lettered. It
+// reports false if ctx was cancelled first.
+func (c *Consumer) store(ctx context.Context, batch []consumer.Message) bool {
+	start := time.Now()
+	pending := batch
+
+	for attempt := 1; ; attempt++ {
+		metrics := make([]models.Metric, len(pending))
+		for i, msg := range pending {
+			metrics[i] = msg.Metric
+		}
+
+		err := c.repo.StoreBranch(ctx, metrics)
+
+		var batchErr *repository.BatchInsertError
+		switch {
+		case err == nil:
+			pending = nil
+		case errors.As(err, &batchErr):
+			c.log.Warn("some metrics failed to be stored", "error", batchErr, "attempt", attempt)
+
+			failed := make([]consumer.Message, 0, len(batchErr.Failed))
+			for _, i := range batchErr.Failed {
+				failed = append(failed, pending[i])
+			}
+			pending = failed
 
-			if err := c.repo.StoreBranch(ctx, []models.Metric{metric}); err != nil {
-				if batchErr, ok := err.(*repository.BatchInsertError); ok {
-					c.log.Warn("some metrics failed to be stored", "error", batchErr)
-					continue
+			if attempt >= c.retry.MaxAttempts {
+				letters := make([]consumer.DeadLetter, len(pending))
+				for i, msg := range pending {
+					letters[i] = consumer.DeadLetter{
+						Key:         msg.Key,
+						Payload:     msg.Payload,
+						ContentType: msg.ContentType,
+						Err:         batchErr.Errors[i],
+						Attempts:    attempt,
+						FailedAt:    time.Now(),
+					}
 				}
 
-				c.log.Error("failed to store metrics", "error", err)
-				continue
+				if !c.deadLetter(ctx, "store", letters...) {
+					return false
+				}
+				pending = nil
 			}
+		default:
+			c.log.Error("failed to store metrics, retrying", "error", err, "count", len(pending), "attempt", attempt)
+		}
+
+		if len(pending) == 0 {
+			c.metrics.BatchSize.Observe(float64(len(batch)))
+			c.metrics.BatchFlushDuration.Observe(time.Since(start).Seconds())
+
+			return true
+		}
 
-			c.log.Info("successfully stored metric", "source", metric.Source, "name", metric.Name, "value", metric.Value)
+		if !c.retry.Wait(ctx, attempt) {
+			return false
 		}
 	}
 }
+
+// deadLetter hands letters to the dead-letter queue, retrying under the
+// retry policy. Letters the queue does not accept, or all of them without a
+// queue, are logged and dropped. It reports false if ctx was cancelled first.
+func (c *Consumer) deadLetter(ctx context.Context, reason string, letters ...consumer.DeadLetter) bool {
+	if c.dlq != nil {
+		err := c.retry.Do(ctx, func() error {
+			return c.dlq.Send(ctx, letters...)
+		}, func(err error, attempt int) {
+			c.log.Error("failed to send messages to dead-letter queue", "error", err, "attempt", attempt)
+		})
+		if err == nil {
+			c.log.Warn("messages sent to dead-letter queue", "reason", reason, "count", len(letters))
+			c.metrics.DeadLettersTotal.WithLabelValues(reason).Add(float64(len(letters)))
+
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+
+	for _, letter := range letters {
+		c.log.Error("dropping message that could not be dead-lettered", "error", letter.Err, "attempts", letter.Attempts)
+	}
+	c.metrics.DeadLettersTotal.WithLabelValues(reason).Add(float64(len(letters)))
+
+	return true
+}
+
+// shutdown makes a last attempt to store and commit the pending batch once
+// the consumer is stopped. Anything left uncommitted is redelivered on the
+// next start.
+func (c *Consumer) shutdown(batch []consumer.Message) {
+	if len(batch) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownFlushTimeout)
+		defer cancel()
+
+		c.flush(ctx, batch)
+	}
+
+	c.log.Info("consumer stopped")
+}
@@ -9,6 +9,9 @@ type BatchInsertError struct {
 	SuccessfullCount int
 	FailedCount      int
 	Errors           []error
+	// Failed holds the positions of the failed metrics in the stored slice,
+	// in the same order as Errors.
+	Failed []int
 }
 
 func (e *BatchInsertError) Error() string {
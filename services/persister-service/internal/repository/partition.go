@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Partition is one table of the partitioned metrics table. Period partitions
+// have no Parent and hold the metrics collected in [Start, End); a zero Start
+// means the period is unbounded below. Each period is split into a partition
+// per Source with its own retention, plus one with an empty Source holding
+// every other source.
+type Partition struct {
+	Name   string
+	Parent string
+	Source string
+	Start  time.Time
+	End    time.Time
+}
+
+type PartitionRepository interface {
+	ListPartitions(ctx context.Context) ([]Partition, error)
+	// CreatePeriodPartition creates the period partition together with its
+	// partition for sources without their own retention, named def.
+	CreatePeriodPartition(ctx context.Context, period Partition, def Partition) error
+	CreateSourcePartition(ctx context.Context, p Partition) error
+	// DropPartition drops p and, for a period, every partition it contains.
+	DropPartition(ctx context.Context, p Partition) error
+}
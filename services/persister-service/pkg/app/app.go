@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/config"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/database"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/metrics"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/partition"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/internal/processor"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/consumer"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/kafka"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/logger"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/nats"
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/rabbitmq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config is aliased so commands outside this module can load and adjust the
+// persister configuration.
+type Config = config.Config
+
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// NewConsumer creates the consumer selected by broker.type. batchSize is
+// batch.size; RabbitMQ's prefetch defaults to twice it.
+func NewConsumer(cfg config.BrokerConfig, batchSize int) (consumer.MessageConsumer, error) {
+	switch cfg.Type {
+	case "kafka":
+		return kafka.NewKafkaConsumer(cfg.Kafka.Brokers, cfg.Kafka.Topic, cfg.Kafka.GroupID), nil
+	case "nats":
+		cons, err := nats.NewNATSConsumer(cfg.NATS.URL, cfg.NATS.Stream, cfg.NATS.Subject, cfg.NATS.Durable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create NATS consumer: %w", err)
+		}
+		return cons, nil
+	case "rabbitmq":
+		prefetch := cfg.RabbitMQ.Prefetch
+		if prefetch <= 0 && batchSize > 0 {
+			// Deliveries stay unacked until their batch is stored, so a
+			// smaller prefetch would never fill a batch.
+			prefetch = 2 * batchSize
+		}
+
+		cons, err := rabbitmq.NewRabbitMQConsumer(cfg.RabbitMQ.URL, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.RoutingKey, cfg.RabbitMQ.Queue, prefetch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RabbitMQ consumer: %w", err)
+		}
+		return cons, nil
+	default:
+		return nil, UnsupportedBrokerError{Type: cfg.Type}
+	}
+}
+
+// NewDeadLetterQueue creates the queue for messages that cannot be stored.
+// Dead-lettering needs a Kafka topic, so for other brokers, or without
+// broker.kafka.dead_letter_topic, it returns nil and failed messages are
+// dropped.
+func NewDeadLetterQueue(cfg config.BrokerConfig) consumer.DeadLetterQueue {
+	if cfg.Type != "kafka" || cfg.Kafka.DeadLetterTopic == "" {
+		return nil
+	}
+
+	return kafka.NewDeadLetterWriter(cfg.Kafka.Brokers, cfg.Kafka.DeadLetterTopic, cfg.Kafka.Topic, cfg.Kafka.GroupID)
+}
+
+// partitionSetupTimeout bounds the partition maintenance, or setting the
+// TimescaleDB retention policy, done before consuming starts.
+const partitionSetupTimeout = 30 * time.Second
+
+// App is the persister pipeline, storing consumed metrics in Postgres. The
+// standalone service and the all-in-one command both run it.
+type App struct {
+	db       *database.Storage
+	consumer consumer.MessageConsumer
+	dlq      consumer.DeadLetterQueue
+	proc     *processor.Consumer
+	// parts is nil in TimescaleDB mode, where chunks are managed by
+	// TimescaleDB itself.
+	parts *partition.Manager
+	log   logger.Logger
+}
+
+// New connects to the database, applies migrations, creates the partitions
+// the incoming metrics need and takes ownership of msgCons and dlq. dlq may
+// be nil.
+func New(cfg *Config, msgCons consumer.MessageConsumer, dlq consumer.DeadLetterQueue, log logger.Logger, reg *prometheus.Registry) (*App, error) {
+	m := metrics.NewMetrics(reg)
+
+	db, err := database.New(cfg.Postgres, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	log.Info("database connected successfully")
+
+	if err := db.RunMigrations(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	log.Info("database migrations applied successfully")
+
+	ctx, cancel := context.WithTimeout(context.Background(), partitionSetupTimeout)
+	defer cancel()
+
+	var parts *partition.Manager
+	if cfg.Postgres.Timescale {
+		if err := setTimescaleRetention(ctx, db, cfg.Retention, log); err != nil {
+			db.Close()
+			return nil, err
+		}
+	} else {
+		parts, err = partition.NewManager(db, log, m, cfg.Partitioning, cfg.Retention)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to create partition manager: %w", err)
+		}
+
+		if err := parts.Maintain(ctx); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to prepare metrics partitions: %w", err)
+		}
+		log.Info("metrics partitions are up to date")
+	}
+
+	return &App{
+		db:       db,
+		consumer: msgCons,
+		dlq:      dlq,
+		proc:     processor.NewConsumer(msgCons, db, dlq, log, m, cfg.Batch, cfg.Retry),
+		parts:    parts,
+		log:      log,
+	}, nil
+}
+
+// Run consumes and maintains the partitions until ctx is cancelled, then
+// closes the consumer, the dead-letter queue and the database.
+func (a *App) Run(ctx context.Context) error {
+	a.log.Info("starting persister-service")
+
+	var wg sync.WaitGroup
+	if a.parts != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.parts.Run(ctx)
+		}()
+	}
+
+	a.proc.Start(ctx)
+	wg.Wait()
+
+	errs := []error{a.consumer.Close(), a.db.Close()}
+	if a.dlq != nil {
+		errs = append(errs, a.dlq.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// setTimescaleRetention applies the retention as a TimescaleDB policy. Chunks
+// hold every source, so all of them are kept for the longest retention.
+func setTimescaleRetention(ctx context.Context, db *database.Storage, cfg config.RetentionConfig, log logger.Logger) error {
+	if len(cfg.Sources) > 0 {
+		log.Warn("per-source retention is not supported with timescale, every source is kept for the longest retention")
+	}
+
+	retention, err := db.SetRetentionPolicy(ctx, partition.LongestRetention(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to set retention policy: %w", err)
+	}
+	log.Info("timescale retention policy set", "retention", retention)
+
+	return nil
+}
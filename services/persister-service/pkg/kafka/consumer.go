@@ -2,46 +2,76 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
-	"time"
+	"fmt"
 
 	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/consumer"
-	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/models"
 	"github.com/segmentio/kafka-go"
 )
 
 type KafkaConsumer struct {
 	reader *kafka.Reader
+
+	// pending holds the last fetched message of every partition; committing
+	// it commits everything before it.
+	pending map[int]kafka.Message
 }
 
 func NewKafkaConsumer(brokers []string, topic, groupID string) consumer.MessageConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        brokers,
-		Topic:          topic,
-		GroupID:        groupID,
-		MinBytes:       10e3,
-		MaxBytes:       10e6,
-		StartOffset:    kafka.LastOffset,
-		CommitInterval: time.Second,
+		Brokers:     brokers,
+		Topic:       topic,
+		GroupID:     groupID,
+		MinBytes:    10e3,
+		MaxBytes:    10e6,
+		StartOffset: kafka.LastOffset,
 	})
-	return &KafkaConsumer{reader: reader}
+	return &KafkaConsumer{
+		reader:  reader,
+		pending: make(map[int]kafka.Message),
+	}
 }
 
-func (k *KafkaConsumer) ConsumeMetric(ctx context.Context) (models.Metric, error) {
-	// log.Println("Kafka Consumer: trying to read message")
-
-	msg, err := k.reader.ReadMessage(ctx)
+func (k *KafkaConsumer) FetchMetric(ctx context.Context) (consumer.Message, error) {
+	msg, err := k.reader.FetchMessage(ctx)
 	if err != nil {
-		// log.Println("Kafka Consumer: failed to read message", err)
-		return models.Metric{}, err
+		return consumer.Message{}, err
+	}
+
+	k.pending[msg.Partition] = msg
+
+	return consumer.DecodeMessage(msg.Key, contentType(msg.Headers), msg.Value)
+}
+
+func (k *KafkaConsumer) Commit(ctx context.Context) error {
+	if len(k.pending) == 0 {
+		return nil
 	}
 
-	// log.Println("Kafka Consumer: read message", string(msg.Value))
+	msgs := make([]kafka.Message, 0, len(k.pending))
+	for _, msg := range k.pending {
+		msgs = append(msgs, msg)
+	}
+
+	if err := k.reader.CommitMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("failed to commit offsets: %w", err)
+	}
+
+	clear(k.pending)
 
-	var metric models.Metric
-	err = json.Unmarshal(msg.Value, &metric)
+	return nil
+}
+
+// contentTypeHeader is set by the collector's producer on every message.
+const contentTypeHeader = "content-type"
+
+func contentType(headers []kafka.Header) string {
+	for _, header := range headers {
+		if header.Key == contentTypeHeader {
+			return string(header.Value)
+		}
+	}
 
-	return metric, err
+	return ""
 }
 
 func (k *KafkaConsumer) Close() error {
@@ -0,0 +1,69 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MatTwix/Ultimate-Metrics-Platform/services/persister-service/pkg/consumer"
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers describing why a message was dead-lettered. The original
+// content-type header is kept as well, so republishing needs no conversion.
+const (
+	HeaderError         = "dlq-error"
+	HeaderAttempts      = "dlq-attempts"
+	HeaderOriginalTopic = "dlq-original-topic"
+	HeaderConsumerGroup = "dlq-consumer-group"
+	HeaderFailedAt      = "dlq-failed-at"
+)
+
+// DeadLetterWriter publishes messages the service gave up on to the
+// dead-letter topic with their original key and payload.
+type DeadLetterWriter struct {
+	writer        *kafka.Writer
+	originalTopic string
+	groupID       string
+}
+
+func NewDeadLetterWriter(brokers []string, topic, originalTopic, groupID string) consumer.DeadLetterQueue {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		WriteTimeout: 10 * time.Second,
+		ReadTimeout:  10 * time.Second,
+	}
+	return &DeadLetterWriter{writer: writer, originalTopic: originalTopic, groupID: groupID}
+}
+
+func (d *DeadLetterWriter) Send(ctx context.Context, letters ...consumer.DeadLetter) error {
+	msgs := make([]kafka.Message, 0, len(letters))
+	for _, letter := range letters {
+		msgs = append(msgs, kafka.Message{
+			Key:   letter.Key,
+			Value: letter.Payload,
+			Headers: []kafka.Header{
+				{Key: contentTypeHeader, Value: []byte(letter.ContentType)},
+				{Key: HeaderError, Value: []byte(letter.Err.Error())},
+				{Key: HeaderAttempts, Value: []byte(strconv.Itoa(letter.Attempts))},
+				{Key: HeaderOriginalTopic, Value: []byte(d.originalTopic)},
+				{Key: HeaderConsumerGroup, Value: []byte(d.groupID)},
+				{Key: HeaderFailedAt, Value: []byte(letter.FailedAt.UTC().Format(time.RFC3339Nano))},
+			},
+		})
+	}
+
+	if err := d.writer.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("failed to write %d messages to dead-letter topic: %w", len(msgs), err)
+	}
+
+	return nil
+}
+
+func (d *DeadLetterWriter) Close() error {
+	return d.writer.Close()
+}
@@ -3,10 +3,16 @@ package models
 import "time"
 
 type Metric struct {
-	ID          int64
+	ID int64
+	// EventID identifies one observation of a series, so a metric that is
+	// delivered more than once is stored once.
+	EventID     string
 	Source      string
 	Name        string
 	Value       float64
 	Labels      map[string]any
 	CollectedAt time.Time
+	// Unit and Description describe the series, when the source knows them.
+	Unit        string
+	Description string
 }
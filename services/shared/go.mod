@@ -0,0 +1,5 @@
+module github.com/MatTwix/Ultimate-Metrics-Platform/services/shared
+
+go 1.24.0
+
+require google.golang.org/protobuf v1.36.10
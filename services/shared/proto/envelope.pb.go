@@ -0,0 +1,391 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v4.25.3
+// source: proto/envelope.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MetricEnvelope is the payload of every message the collector publishes
+// with content type application/x-protobuf. Fields are only ever added, a
+// breaking change bumps schema_version.
+type MetricEnvelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion uint32                 `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Metric        *Metric                `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricEnvelope) Reset() {
+	*x = MetricEnvelope{}
+	mi := &file_proto_envelope_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricEnvelope) ProtoMessage() {}
+
+func (x *MetricEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_envelope_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricEnvelope.ProtoReflect.Descriptor instead.
+func (*MetricEnvelope) Descriptor() ([]byte, []int) {
+	return file_proto_envelope_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MetricEnvelope) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *MetricEnvelope) GetMetric() *Metric {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+type Metric struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value       float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	Labels      map[string]*LabelValue `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CollectedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	// event_id is the same for every delivery of one observation, so
+	// consumers can drop duplicates.
+	EventId string `protobuf:"bytes,6,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// unit and description describe the series and are empty when the
+	// source does not know them.
+	Unit          string `protobuf:"bytes,7,opt,name=unit,proto3" json:"unit,omitempty"`
+	Description   string `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metric) Reset() {
+	*x = Metric{}
+	mi := &file_proto_envelope_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_envelope_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_proto_envelope_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Metric) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Metric) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Metric) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Metric) GetLabels() map[string]*LabelValue {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Metric) GetCollectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CollectedAt
+	}
+	return nil
+}
+
+func (x *Metric) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Metric) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Metric) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// LabelValue keeps the type of a label, which JSON payloads lose for
+// numbers.
+type LabelValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*LabelValue_StringValue
+	//	*LabelValue_IntValue
+	//	*LabelValue_DoubleValue
+	//	*LabelValue_BoolValue
+	Value         isLabelValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LabelValue) Reset() {
+	*x = LabelValue{}
+	mi := &file_proto_envelope_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelValue) ProtoMessage() {}
+
+func (x *LabelValue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_envelope_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelValue.ProtoReflect.Descriptor instead.
+func (*LabelValue) Descriptor() ([]byte, []int) {
+	return file_proto_envelope_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LabelValue) GetValue() isLabelValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *LabelValue) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*LabelValue_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *LabelValue) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*LabelValue_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *LabelValue) GetDoubleValue() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*LabelValue_DoubleValue); ok {
+			return x.DoubleValue
+		}
+	}
+	return 0
+}
+
+func (x *LabelValue) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*LabelValue_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+type isLabelValue_Value interface {
+	isLabelValue_Value()
+}
+
+type LabelValue_StringValue struct {
+	StringValue string `protobuf:"bytes,1,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type LabelValue_IntValue struct {
+	IntValue int64 `protobuf:"varint,2,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type LabelValue_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,3,opt,name=double_value,json=doubleValue,proto3,oneof"`
+}
+
+type LabelValue_BoolValue struct {
+	BoolValue bool `protobuf:"varint,4,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+func (*LabelValue_StringValue) isLabelValue_Value() {}
+
+func (*LabelValue_IntValue) isLabelValue_Value() {}
+
+func (*LabelValue_DoubleValue) isLabelValue_Value() {}
+
+func (*LabelValue_BoolValue) isLabelValue_Value() {}
+
+var File_proto_envelope_proto protoreflect.FileDescriptor
+
+const file_proto_envelope_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/envelope.proto\x12\n" +
+	"metrics.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"c\n" +
+	"\x0eMetricEnvelope\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\rR\rschemaVersion\x12*\n" +
+	"\x06metric\x18\x02 \x01(\v2\x12.metrics.v1.MetricR\x06metric\"\xe5\x02\n" +
+	"\x06Metric\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x126\n" +
+	"\x06labels\x18\x04 \x03(\v2\x1e.metrics.v1.Metric.LabelsEntryR\x06labels\x12=\n" +
+	"\fcollected_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcollectedAt\x12\x19\n" +
+	"\bevent_id\x18\x06 \x01(\tR\aeventId\x12\x12\n" +
+	"\x04unit\x18\a \x01(\tR\x04unit\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x1aQ\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.metrics.v1.LabelValueR\x05value:\x028\x01\"\x9f\x01\n" +
+	"\n" +
+	"LabelValue\x12#\n" +
+	"\fstring_value\x18\x01 \x01(\tH\x00R\vstringValue\x12\x1d\n" +
+	"\tint_value\x18\x02 \x01(\x03H\x00R\bintValue\x12#\n" +
+	"\fdouble_value\x18\x03 \x01(\x01H\x00R\vdoubleValue\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x04 \x01(\bH\x00R\tboolValueB\a\n" +
+	"\x05valueBDZBgithub.com/MatTwix/Ultimate-Metrics-Platform/services/shared/protob\x06proto3"
+
+var (
+	file_proto_envelope_proto_rawDescOnce sync.Once
+	file_proto_envelope_proto_rawDescData []byte
+)
+
+func file_proto_envelope_proto_rawDescGZIP() []byte {
+	file_proto_envelope_proto_rawDescOnce.Do(func() {
+		file_proto_envelope_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_envelope_proto_rawDesc), len(file_proto_envelope_proto_rawDesc)))
+	})
+	return file_proto_envelope_proto_rawDescData
+}
+
+var file_proto_envelope_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_envelope_proto_goTypes = []any{
+	(*MetricEnvelope)(nil),        // 0: metrics.v1.MetricEnvelope
+	(*Metric)(nil),                // 1: metrics.v1.Metric
+	(*LabelValue)(nil),            // 2: metrics.v1.LabelValue
+	nil,                           // 3: metrics.v1.Metric.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_proto_envelope_proto_depIdxs = []int32{
+	1, // 0: metrics.v1.MetricEnvelope.metric:type_name -> metrics.v1.Metric
+	3, // 1: metrics.v1.Metric.labels:type_name -> metrics.v1.Metric.LabelsEntry
+	4, // 2: metrics.v1.Metric.collected_at:type_name -> google.protobuf.Timestamp
+	2, // 3: metrics.v1.Metric.LabelsEntry.value:type_name -> metrics.v1.LabelValue
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_envelope_proto_init() }
+func file_proto_envelope_proto_init() {
+	if File_proto_envelope_proto != nil {
+		return
+	}
+	file_proto_envelope_proto_msgTypes[2].OneofWrappers = []any{
+		(*LabelValue_StringValue)(nil),
+		(*LabelValue_IntValue)(nil),
+		(*LabelValue_DoubleValue)(nil),
+		(*LabelValue_BoolValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_envelope_proto_rawDesc), len(file_proto_envelope_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_envelope_proto_goTypes,
+		DependencyIndexes: file_proto_envelope_proto_depIdxs,
+		MessageInfos:      file_proto_envelope_proto_msgTypes,
+	}.Build()
+	File_proto_envelope_proto = out.File
+	file_proto_envelope_proto_goTypes = nil
+	file_proto_envelope_proto_depIdxs = nil
+}
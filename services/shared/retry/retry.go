@@ -0,0 +1,93 @@
+// Package retry spaces out attempts to handle a message with exponential
+// backoff. The consuming services share it so they retry the same way.
+package retry
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2
+)
+
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+}
+
+// NewPolicy replaces unset or invalid settings with the defaults.
+func NewPolicy(maxAttempts int, initialBackoff, maxBackoff time.Duration, multiplier float64) Policy {
+	p := Policy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: initialBackoff,
+		MaxBackoff:     maxBackoff,
+		Multiplier:     multiplier,
+	}
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = max(defaultMaxBackoff, p.InitialBackoff)
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultMultiplier
+	}
+
+	return p
+}
+
+// Backoff returns the delay after the given failed attempt, counted from 1.
+func (p Policy) Backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+
+	return time.Duration(delay)
+}
+
+// Wait sleeps for the backoff after attempt and reports false if ctx was
+// cancelled first.
+func (p Policy) Wait(ctx context.Context, attempt int) bool {
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Do calls op until it succeeds or MaxAttempts attempts have failed, and
+// passes every failure to onFailure. It returns the last error, or the
+// error of ctx if it was cancelled while waiting.
+func (p Policy) Do(ctx context.Context, op func() error, onFailure func(err error, attempt int)) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+
+		onFailure(err, attempt)
+
+		if attempt >= p.MaxAttempts {
+			return err
+		}
+		if !p.Wait(ctx, attempt) {
+			return ctx.Err()
+		}
+	}
+}